@@ -0,0 +1,85 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"gymlog/domain"
+	"strings"
+)
+
+var ErrAccessTokenNotFound = newError(KindNotFound, "access_token_not_found", "access token not found")
+
+// CreateAccessToken saves a new access token for the user and returns it with its secret, which is
+// only stored hashed and cannot be shown again.
+func (r *UserRepo) CreateAccessToken(userID int, token domain.AccessToken) (domain.AccessToken, string, error) {
+	secret, err := newSecret(domain.AccessTokenPrefix)
+	if err != nil {
+		return domain.AccessToken{}, "", err
+	}
+	token.UserID = userID
+	token.TokenHash = hashToken(secret)
+	token.CreatedAt = r.now().UTC()
+	token.LastUsedAt = nil
+
+	tokenID, err := r.storage.SaveAccessToken(token)
+	if err != nil {
+		return domain.AccessToken{}, "", err
+	}
+	token.ID = tokenID
+	return token, secret, nil
+}
+
+// AccessToken returns the access token of a secret, unknown and expired tokens are reported as not found.
+func (r *UserRepo) AccessToken(secret string) (domain.AccessToken, error) {
+	if !strings.HasPrefix(secret, domain.AccessTokenPrefix) {
+		return domain.AccessToken{}, ErrAccessTokenNotFound
+	}
+	token, err := r.storage.AccessToken(hashToken(secret))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.AccessToken{}, ErrAccessTokenNotFound
+	}
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+	if token.IsExpired(r.now()) {
+		return domain.AccessToken{}, ErrAccessTokenNotFound
+	}
+	return token, nil
+}
+
+// TouchAccessToken records that the access token was just used.
+func (r *UserRepo) TouchAccessToken(token domain.AccessToken) error {
+	now := r.now().UTC()
+	if token.LastUsedAt != nil && now.Sub(*token.LastUsedAt) < sessionTouchInterval {
+		return nil
+	}
+	return r.storage.TouchAccessToken(token.ID, now)
+}
+
+// AccessTokens returns the access tokens of the user, the newest first.
+func (r *UserRepo) AccessTokens(userID int) ([]domain.AccessToken, error) {
+	return r.storage.AccessTokens(userID)
+}
+
+// AccessTokenByID returns an access token of the user as listed by AccessTokens.
+func (r *UserRepo) AccessTokenByID(userID int, tokenID int) (domain.AccessToken, error) {
+	tokens, err := r.storage.AccessTokens(userID)
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+	for _, token := range tokens {
+		if token.ID == tokenID {
+			return token, nil
+		}
+	}
+	return domain.AccessToken{}, ErrAccessTokenNotFound
+}
+
+// DeleteAccessToken revokes an access token of the user.
+func (r *UserRepo) DeleteAccessToken(userID int, tokenID int) error {
+	err := r.storage.DeleteAccessToken(userID, tokenID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAccessTokenNotFound
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"gymlog/adapters/storage"
+	"gymlog/domain"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSameEmail              = newError(KindInvalid, "same_email", "email is already the email of the account")
+	ErrNoAccountDeletion      = newError(KindConflict, "no_account_deletion", "account is not scheduled for deletion")
+	ErrAccountDeletionPending = newError(KindConflict, "account_deletion_pending", "account is already scheduled for deletion")
+)
+
+// ChangePassword changes the password of the user, logs out every session but the one making
+// the change and revokes the access tokens. The caller checks the current password and the
+// password policy.
+func (r *UserRepo) ChangePassword(userID int, sessionID int, passwordHash string) error {
+	err := r.storage.ChangePassword(userID, passwordHash, sessionID, r.now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// ChangeEmail changes the email of the user, which is not verified until they use the token
+// sent by SendEmailVerification to the new email.
+func (r *UserRepo) ChangeEmail(userID int, email string) error {
+	user, err := r.User(userID)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, email) {
+		return ErrSameEmail
+	}
+
+	err = r.storage.ChangeEmail(userID, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if errors.Is(err, storage.ErrEmailTaken) {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+// ScheduleAccountDeletion schedules the deletion of the user and all their data after the grace
+// period and returns when it will happen. The account keeps working until then, so the user can
+// log in and cancel it.
+func (r *UserRepo) ScheduleAccountDeletion(userID int) (time.Time, error) {
+	user, err := r.User(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user.DeletionPending() {
+		return time.Time{}, ErrAccountDeletionPending
+	}
+
+	deleteAt := r.now().UTC().Add(domain.AccountDeletionGracePeriod)
+	err = r.storage.ScheduleUserDeletion(userID, &deleteAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, ErrUserNotFound
+	}
+	return deleteAt, err
+}
+
+// CancelAccountDeletion keeps the account of a user that asked to delete it.
+func (r *UserRepo) CancelAccountDeletion(userID int) error {
+	user, err := r.User(userID)
+	if err != nil {
+		return err
+	}
+	if !user.DeletionPending() {
+		return ErrNoAccountDeletion
+	}
+
+	err = r.storage.ScheduleUserDeletion(userID, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// DeleteScheduledAccounts deletes the accounts whose grace period is over, with their routines,
+// workouts, sessions and the rest of their data, and returns how many were deleted.
+func (r *UserRepo) DeleteScheduledAccounts() (int, error) {
+	return r.storage.DeleteScheduledUsers(r.now().UTC())
+}
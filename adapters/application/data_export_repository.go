@@ -0,0 +1,282 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gymlog/domain"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrDataExportNotFound = newError(KindNotFound, "data_export_not_found", "data export not found")
+	ErrDataExportPending  = newError(KindConflict, "data_export_pending", "a data export is already being prepared")
+	ErrDataExportNotReady = newError(KindConflict, "data_export_not_ready", "data export is not ready")
+	ErrDataExportExpired  = newError(KindGone, "data_export_expired", "data export expired")
+)
+
+// dataExportClaimTimeout is how long an export can stay building before another builder claims
+// it again, in case the one that claimed it died.
+const dataExportClaimTimeout = 30 * time.Minute
+
+// RequestDataExport queues an export of all the data of the user, BuildDataExports builds it in
+// the background. A user can only have one export in progress at a time.
+func (r *UserRepo) RequestDataExport(userID int) (domain.DataExport, error) {
+	exports, err := r.storage.DataExports(userID)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	for _, export := range exports {
+		if export.InProgress() {
+			return domain.DataExport{}, ErrDataExportPending
+		}
+	}
+
+	export := domain.DataExport{UserID: userID, Status: domain.DataExportPending, CreatedAt: r.now().UTC()}
+	exportID, err := r.storage.SaveDataExport(export)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	export.ID = exportID
+	return export, nil
+}
+
+// DataExports returns the data exports of the user that did not expire, the newest first.
+func (r *UserRepo) DataExports(userID int) ([]domain.DataExport, error) {
+	exports, err := r.storage.DataExports(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := r.now()
+	return slices.DeleteFunc(exports, func(export domain.DataExport) bool {
+		return export.IsExpired(now)
+	}), nil
+}
+
+// DataExport returns a data export of the user, expired exports are reported as not found.
+func (r *UserRepo) DataExport(userID int, exportID int) (domain.DataExport, error) {
+	export, err := r.storage.DataExport(userID, exportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.DataExport{}, ErrDataExportNotFound
+	}
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	if export.IsExpired(r.now()) {
+		return domain.DataExport{}, ErrDataExportNotFound
+	}
+	return export, nil
+}
+
+// DataExportArchive returns the ZIP archive of a ready data export of the user until it expires.
+func (r *UserRepo) DataExportArchive(userID int, exportID int) ([]byte, error) {
+	export, err := r.storage.DataExport(userID, exportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDataExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if export.IsExpired(r.now()) {
+		return nil, ErrDataExportExpired
+	}
+	if export.Status != domain.DataExportReady {
+		return nil, ErrDataExportNotReady
+	}
+
+	archive, err := r.storage.DataExportArchive(userID, exportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted since it was read, because it expired or the user was deleted.
+		return nil, ErrDataExportExpired
+	}
+	return archive, err
+}
+
+// BuildDataExports claims the pending data exports, builds their archives and returns how many
+// are ready. Claiming first keeps two builders from building the same export. Exports that
+// cannot be built are marked as failed, so they are not retried forever.
+func (r *UserRepo) BuildDataExports() (int, error) {
+	claimedAt := r.now().UTC()
+	exports, err := r.storage.ClaimDataExports(claimedAt, claimedAt.Add(-dataExportClaimTimeout))
+	if err != nil {
+		return 0, err
+	}
+
+	built := 0
+	var errs []error
+	for _, export := range exports {
+		status := domain.DataExportReady
+		archive, err := r.buildDataExportArchive(export.UserID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("data export %d: %w", export.ID, err))
+			status, archive = domain.DataExportFailed, nil
+		}
+
+		now := r.now().UTC()
+		err = r.storage.FinishDataExport(export.ID, status, archive, now, now.Add(domain.DataExportTTL))
+		if errors.Is(err, sql.ErrNoRows) {
+			// The user was deleted while the export was being built, or the export took so long
+			// that another builder claimed it again and finished it first.
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("data export %d: %w", export.ID, err))
+			continue
+		}
+		if status == domain.DataExportReady {
+			built++
+		}
+	}
+	return built, errors.Join(errs...)
+}
+
+// DeleteExpiredDataExports deletes the data exports that can no longer be downloaded and returns
+// how many were deleted.
+func (r *UserRepo) DeleteExpiredDataExports() (int, error) {
+	return r.storage.DeleteExpiredDataExports(r.now().UTC())
+}
+
+// exportedUser is the account in user.json, without the password hash or other secrets.
+type exportedUser struct {
+	ID                  int        `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+	TwoFactorEnabled    bool       `json:"two_factor_enabled"`
+}
+
+// buildDataExportArchive gathers everything gymlog holds about the user into a ZIP archive. Data
+// that only makes sense as a whole is exported as JSON, in the shape the API returns it, and
+// lists of records as CSV. Tokens, secrets and password hashes are left out.
+func (r *UserRepo) buildDataExportArchive(userID int) ([]byte, error) {
+	user, err := r.storage.User(userID)
+	if err != nil {
+		return nil, err
+	}
+	twoFactorEnabled, err := r.TOTPEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := r.storage.Sessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	accessTokens, err := r.storage.AccessTokens(userID)
+	if err != nil {
+		return nil, err
+	}
+	routines, err := r.storage.Routines(userID)
+	if err != nil {
+		return nil, err
+	}
+	exercises, err := r.storage.Exercises(userID, domain.ExerciseQuery{Sort: domain.SortByID})
+	if err != nil {
+		return nil, err
+	}
+	// The catalog exercises are the same for everyone, only the ones the user created are theirs.
+	exercises = slices.DeleteFunc(exercises, func(exercise domain.Exercise) bool {
+		return exercise.OwnerID == nil || *exercise.OwnerID != userID
+	})
+	workouts, err := r.storage.Workouts(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	err = errors.Join(
+		writeJSONFile(archive, "user.json", exportedUser{
+			ID:                  user.ID,
+			Username:            user.Username,
+			Email:               user.Email,
+			EmailVerifiedAt:     user.EmailVerifiedAt,
+			DeletionScheduledAt: user.DeletionScheduledAt,
+			TwoFactorEnabled:    twoFactorEnabled,
+		}),
+		writeCSVFile(archive, "sessions.csv", sessionRecords(sessions)),
+		writeCSVFile(archive, "access_tokens.csv", accessTokenRecords(accessTokens)),
+		writeJSONFile(archive, "routines.json", routines),
+		writeJSONFile(archive, "exercises.json", exercises),
+		writeJSONFile(archive, "workouts.json", workouts),
+		writeCSVFile(archive, "workout_sets.csv", workoutSetRecords(workouts)),
+		archive.Close(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSONFile(archive *zip.Writer, name string, data any) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func writeCSVFile(archive *zip.Writer, name string, records [][]string) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	return csv.NewWriter(w).WriteAll(records)
+}
+
+func sessionRecords(sessions []domain.UserSession) [][]string {
+	records := [][]string{{"id", "device_name", "user_agent", "ip", "created_at", "last_seen_at", "expires_at"}}
+	for _, session := range sessions {
+		records = append(records, []string{
+			strconv.Itoa(session.ID), session.DeviceName, session.UserAgent, session.IP,
+			formatTime(&session.CreatedAt), formatTime(&session.LastSeenAt), formatTime(&session.ExpiresAt),
+		})
+	}
+	return records
+}
+
+func accessTokenRecords(tokens []domain.AccessToken) [][]string {
+	records := [][]string{{"id", "name", "scope", "created_at", "expires_at", "last_used_at"}}
+	for _, token := range tokens {
+		records = append(records, []string{
+			strconv.Itoa(token.ID), token.Name, string(token.Scope),
+			formatTime(&token.CreatedAt), formatTime(token.ExpiresAt), formatTime(token.LastUsedAt),
+		})
+	}
+	return records
+}
+
+// workoutSetRecords has a row per set, with the workout it belongs to, to open the history in a spreadsheet.
+func workoutSetRecords(workouts []domain.Workout) [][]string {
+	records := [][]string{{"workout_id", "routine_id", "started_at", "finished_at", "exercise_id", "set_index", "reps", "weight", "completed"}}
+	for _, workout := range workouts {
+		routineID := ""
+		if workout.RoutineID != nil {
+			routineID = strconv.Itoa(*workout.RoutineID)
+		}
+		for _, set := range workout.Sets {
+			records = append(records, []string{
+				strconv.Itoa(workout.ID), routineID, formatTime(&workout.StartedAt), formatTime(workout.FinishedAt),
+				strconv.Itoa(set.ExerciseID), strconv.Itoa(set.SetIndex), strconv.Itoa(set.Reps),
+				strconv.FormatFloat(set.Weight, 'f', -1, 64), strconv.FormatBool(set.Completed),
+			})
+		}
+	}
+	return records
+}
+
+// formatTime formats times for the CSV files as RFC 3339 in UTC, nil times are empty.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
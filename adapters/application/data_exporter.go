@@ -0,0 +1,28 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunDataExporter builds the pending data exports every interval until the context is done.
+func RunDataExporter(ctx context.Context, exports DataExportRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			built, err := exports.BuildDataExports()
+			if err != nil {
+				log.Println("Error building data exports:", err)
+			}
+			if built > 0 {
+				log.Printf("Built %d data exports", built)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gymlog/adapters/mailer"
+	"gymlog/domain"
+	"time"
+)
+
+var (
+	ErrInvalidEmailVerification  = newError(KindInvalid, "invalid_email_verification", "invalid or expired email verification token")
+	ErrEmailAlreadyVerified      = newError(KindConflict, "email_already_verified", "email is already verified")
+	ErrTooManyEmailVerifications = newError(KindTooManyRequests, "too_many_email_verifications", "too many verification emails, try again later")
+)
+
+const (
+	// emailVerificationInterval is how long a user has to wait before asking for another verification email.
+	emailVerificationInterval = time.Minute
+	// emailVerificationsPerDay is how many verification emails a user can ask for in a day.
+	emailVerificationsPerDay = 5
+)
+
+// SendEmailVerification emails the user a token to verify their email. Users can only ask for
+// a few emails a day, and not too often, so the endpoint cannot be used to flood an inbox.
+func (r *UserRepo) SendEmailVerification(ctx context.Context, userID int) error {
+	user, err := r.User(userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	now := r.now().UTC()
+	recent, err := r.storage.EmailVerifications(userID, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	// The interval only applies to the same email, so a user who just changed it gets the new token.
+	if len(recent) >= emailVerificationsPerDay || len(recent) > 0 && recent[0].Email == user.Email && now.Sub(recent[0].CreatedAt) < emailVerificationInterval {
+		return ErrTooManyEmailVerifications
+	}
+
+	secret, err := newSecret("")
+	if err != nil {
+		return err
+	}
+	_, err = r.storage.SaveEmailVerification(domain.EmailVerification{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashToken(secret),
+		CreatedAt: now,
+		ExpiresAt: now.Add(domain.EmailVerificationTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your gymlog email",
+		Body: fmt.Sprintf("Welcome to gymlog, %s.\n\n"+
+			"Use this token in the next %v to verify your email:\n\n%s\n\n"+
+			"If you did not create a gymlog account, ignore this email.",
+			user.Username, domain.EmailVerificationTTL, secret),
+	})
+}
+
+// VerifyEmail marks the email of the user of a verification token as verified, the token can
+// only be used once and only while the user still has the email it was sent to.
+func (r *UserRepo) VerifyEmail(secret string) error {
+	verification, err := r.storage.EmailVerification(hashToken(secret))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidEmailVerification
+	}
+	if err != nil {
+		return err
+	}
+	now := r.now().UTC()
+	if !verification.IsUsable(now) {
+		return ErrInvalidEmailVerification
+	}
+
+	err = r.storage.VerifyEmail(verification.ID, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The token was used first by another request, or the user changed their email since.
+		return ErrInvalidEmailVerification
+	}
+	return err
+}
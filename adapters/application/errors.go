@@ -0,0 +1,36 @@
+package application
+
+// ErrorKind groups the errors of the application by what went wrong, so the server can answer
+// each kind with the same status code.
+type ErrorKind int
+
+const (
+	// KindInvalid is a request that can never succeed as it is, like an invalid routine.
+	KindInvalid ErrorKind = iota + 1
+	// KindUnauthorized is a login or token that could not be verified.
+	KindUnauthorized
+	// KindForbidden is an action the user is not allowed to do, like using a wrong password.
+	KindForbidden
+	KindNotFound
+	// KindConflict is a request that clashes with the current state, like a taken username.
+	KindConflict
+	// KindGone is something that existed but can no longer be used, like an expired data export.
+	KindGone
+	KindTooManyRequests
+)
+
+// Error is an expected error of the application, with a stable Code for API clients and a
+// Message safe to show to the user. Errors that are not an Error are internal errors.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func newError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"gymlog/adapters/storage"
+	"gymlog/domain"
+)
+
+var (
+	ErrExerciseNotFound = newError(KindNotFound, "exercise_not_found", "exercise not found")
+	ErrExerciseInUse    = newError(KindConflict, "exercise_in_use", "exercise is used by routines or workouts")
+)
+
+// Exercises returns a page of the global exercises plus the ones created by the user that match the query.
+func (r *GymRepository) Exercises(userID int, query domain.ExerciseQuery) (domain.ExercisePage, error) {
+	// Ask for one more exercise than requested to know whether there is a next page.
+	limit := query.Limit
+	if limit > 0 {
+		query.Limit++
+	}
+	exercises, err := r.storage.Exercises(userID, query)
+	if err != nil {
+		return domain.ExercisePage{}, err
+	}
+
+	page := domain.ExercisePage{Exercises: exercises}
+	if limit > 0 && len(exercises) > limit {
+		page.Exercises = exercises[:limit]
+		page.NextCursor = query.CursorAfter(page.Exercises[limit-1]).String()
+	}
+	return page, nil
+}
+
+// GetExercise returns an exercise that is global or was created by the user.
+func (r *GymRepository) GetExercise(userID int, exerciseID int) (domain.Exercise, error) {
+	exercise, err := r.storage.Exercise(userID, exerciseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Exercise{}, ErrExerciseNotFound
+	}
+	if err != nil {
+		return domain.Exercise{}, err
+	}
+	return exercise, nil
+}
+
+// CreateExercise saves a private exercise for the user and returns it with its ID.
+func (r *GymRepository) CreateExercise(userID int, exercise domain.Exercise) (domain.Exercise, error) {
+	exerciseID, err := r.storage.SaveExercise(userID, exercise)
+	if err != nil {
+		return domain.Exercise{}, err
+	}
+	exercise.ID = exerciseID
+	exercise.OwnerID = &userID
+	return exercise, nil
+}
+
+// UpdateExercise updates a private exercise of the user, global exercises are reported as not found.
+func (r *GymRepository) UpdateExercise(userID int, exercise domain.Exercise) error {
+	err := r.storage.UpdateExercise(userID, exercise)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrExerciseNotFound
+	}
+	return err
+}
+
+func (r *GymRepository) DeleteExercise(userID int, exerciseID int) error {
+	err := r.storage.DeleteExercise(userID, exerciseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrExerciseNotFound
+	}
+	if errors.Is(err, storage.ErrExerciseInUse) {
+		return ErrExerciseInUse
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package application
+
+import (
+	"gymlog/domain"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// loginAttemptsChecked is how many recent attempts are read to compute a lockout, the wait
+	// reaches its maximum long before.
+	loginAttemptsChecked = 50
+	// loginAttemptRetention is how long the login attempts are kept for auditing.
+	loginAttemptRetention = 90 * 24 * time.Hour
+	// maxUsernameLength is the length of the username column, longer ones are cut in the audit log.
+	maxUsernameLength = 255
+)
+
+// LoginDelay returns how long the client has to wait before trying to log in to the username
+// again, 0 when it can try now. Both the failed logins of the account and the ones from the IP
+// count, so guessing the password of one account or the accounts of many users is slowed down.
+func (r *UserRepo) LoginDelay(username, ip string) (time.Duration, error) {
+	now := r.now().UTC()
+	username = truncate(domain.NormalizeUsername(username), maxUsernameLength)
+
+	attempts, err := r.storage.LoginAttempts(username, now.Add(-r.accountLockout.Window), loginAttemptsChecked)
+	if err != nil {
+		return 0, err
+	}
+	// A successful login starts over, only the failures after it count.
+	var accountFailures []domain.LoginAttempt
+	for _, attempt := range attempts {
+		if attempt.Outcome == domain.LoginSucceeded {
+			break
+		}
+		accountFailures = append(accountFailures, attempt)
+	}
+
+	ipFailures, err := r.storage.LoginFailuresByIP(ip, now.Add(-r.ipLockout.Window), loginAttemptsChecked)
+	if err != nil {
+		return 0, err
+	}
+
+	delay := max(lockedUntil(r.accountLockout, accountFailures).Sub(now), lockedUntil(r.ipLockout, ipFailures).Sub(now))
+	return max(delay, 0), nil
+}
+
+// lockedUntil applies the policy to failures sorted the newest first.
+func lockedUntil(policy domain.LockoutPolicy, failures []domain.LoginAttempt) time.Time {
+	if len(failures) == 0 {
+		return time.Time{}
+	}
+	return policy.LockedUntil(len(failures), failures[0].CreatedAt)
+}
+
+// RecordLoginAttempt saves the outcome of a login with a password in the audit log, with the
+// username normalized so that changing its case does not escape a lockout.
+func (r *UserRepo) RecordLoginAttempt(username, ip string, outcome domain.LoginOutcome) error {
+	return r.storage.SaveLoginAttempt(domain.LoginAttempt{
+		Username:  truncate(domain.NormalizeUsername(username), maxUsernameLength),
+		IP:        ip,
+		Outcome:   outcome,
+		CreatedAt: r.now().UTC(),
+	})
+}
+
+// DeleteOldLoginAttempts deletes the login attempts past their retention and returns how many
+// were deleted.
+func (r *UserRepo) DeleteOldLoginAttempts() (int, error) {
+	return r.storage.DeleteLoginAttempts(r.now().UTC().Add(-loginAttemptRetention))
+}
+
+// truncate cuts s to at most n characters, which is how the VARCHAR columns count their length.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
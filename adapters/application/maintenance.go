@@ -0,0 +1,47 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunMaintenance runs the periodic cleanup every interval until the context is done: it deletes
+// the expired sessions and data exports and the login attempts past their retention, and
+// permanently deletes the accounts whose deletion grace period is over.
+func RunMaintenance(ctx context.Context, users UserRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := users.DeleteExpiredSessions()
+			if err != nil {
+				log.Println("Error deleting expired sessions:", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d expired sessions", deleted)
+			}
+			deleted, err = users.DeleteOldLoginAttempts()
+			if err != nil {
+				log.Println("Error deleting old login attempts:", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d old login attempts", deleted)
+			}
+			deleted, err = users.DeleteExpiredDataExports()
+			if err != nil {
+				log.Println("Error deleting expired data exports:", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d expired data exports", deleted)
+			}
+			deleted, err = users.DeleteScheduledAccounts()
+			if err != nil {
+				log.Println("Error deleting scheduled accounts:", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d accounts scheduled for deletion", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"gymlog/adapters/auth"
+	"gymlog/domain"
+)
+
+var (
+	ErrMFAAlreadyEnabled   = newError(KindConflict, "mfa_already_enabled", "two-factor authentication is already enabled")
+	ErrMFANotEnrolled      = newError(KindInvalid, "mfa_not_enrolled", "start the two-factor authentication setup first")
+	ErrMFANotEnabled       = newError(KindConflict, "mfa_not_enabled", "two-factor authentication is not enabled")
+	ErrInvalidMFACode      = newError(KindInvalid, "invalid_mfa_code", "invalid two-factor code")
+	ErrInvalidMFAChallenge = newError(KindUnauthorized, "invalid_mfa_challenge", "invalid or expired login, log in again")
+)
+
+// totpIssuer is the name authenticator apps show next to the codes.
+const totpIssuer = "gymlog"
+
+// EnrollTOTP starts setting up an authenticator app for the user, it returns the secret and the
+// otpauth:// URI to show as a QR code. Logins need codes once ConfirmTOTP gets a first code.
+func (r *UserRepo) EnrollTOTP(userID int) (string, string, error) {
+	user, err := r.User(userID)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = r.storage.SaveTOTP(domain.TOTP{UserID: userID, Secret: secret, CreatedAt: r.now().UTC()})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return secret, auth.TOTPURI(totpIssuer, user.Username, secret), nil
+}
+
+// ConfirmTOTP enables two-factor authentication with the first code of the authenticator app
+// and returns the recovery codes, which are only shown here.
+func (r *UserRepo) ConfirmTOTP(userID int, code string) ([]string, error) {
+	totp, err := r.storage.TOTP(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMFANotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if totp.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	now := r.now().UTC()
+	step, ok := auth.ValidateTOTP(totp.Secret, code, now)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes := make([]string, 0, domain.RecoveryCodeCount)
+	hashes := make([]string, 0, domain.RecoveryCodeCount)
+	for range domain.RecoveryCodeCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	err = r.storage.ConfirmTOTP(userID, now, step, hashes)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another request confirmed it first.
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// TOTPEnabled reports whether the logins of the user need a second factor.
+func (r *UserRepo) TOTPEnabled(userID int) (bool, error) {
+	totp, err := r.storage.TOTP(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return totp.Enabled(), nil
+}
+
+// DisableTOTP turns off two-factor authentication, deleting the recovery codes too. Callers
+// must check the password of the user first.
+func (r *UserRepo) DisableTOTP(userID int) error {
+	enabled, err := r.TOTPEnabled(userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrMFANotEnabled
+	}
+	return r.storage.DeleteTOTP(userID)
+}
+
+// StartMFAChallenge is called after the password of a user with two-factor authentication was
+// checked, it returns the token that CompleteMFAChallenge needs with the second factor.
+func (r *UserRepo) StartMFAChallenge(userID int) (string, error) {
+	secret, err := newSecret("")
+	if err != nil {
+		return "", err
+	}
+	now := r.now().UTC()
+	_, err = r.storage.SaveMFAChallenge(domain.MFAChallenge{
+		UserID:    userID,
+		TokenHash: hashToken(secret),
+		CreatedAt: now,
+		ExpiresAt: now.Add(domain.MFAChallengeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// CompleteMFAChallenge checks the second factor of a login, a code of the authenticator app or a
+// recovery code, and returns the ID of the user, also with ErrInvalidMFACode. Every code only
+// works once, and a challenge only accepts a few wrong codes.
+func (r *UserRepo) CompleteMFAChallenge(secret string, code string) (int, error) {
+	challenge, err := r.storage.MFAChallenge(hashToken(secret))
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return 0, err
+	}
+	now := r.now().UTC()
+	if !challenge.IsUsable(now) {
+		if err := r.storage.DeleteMFAChallenge(challenge.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+		return 0, ErrInvalidMFAChallenge
+	}
+
+	totp, err := r.storage.TOTP(challenge.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.useSecondFactor(totp, code)
+	if errors.Is(err, ErrInvalidMFACode) {
+		if err := r.storage.FailMFAChallenge(challenge.ID); err != nil {
+			return 0, err
+		}
+		return challenge.UserID, ErrInvalidMFACode
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.storage.DeleteMFAChallenge(challenge.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another request completed the login first.
+		return 0, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return 0, err
+	}
+	return challenge.UserID, nil
+}
+
+// useSecondFactor accepts a code of the authenticator app that was not used yet, or else an
+// unused recovery code.
+func (r *UserRepo) useSecondFactor(totp domain.TOTP, code string) error {
+	now := r.now().UTC()
+	if step, ok := auth.ValidateTOTP(totp.Secret, code, now); ok {
+		err := r.storage.UseTOTPStep(totp.UserID, step)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+
+	err := r.storage.UseRecoveryCode(totp.UserID, hashRecoveryCode(code), now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidMFACode
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gymlog/adapters/mailer"
+	"gymlog/domain"
+	"time"
+)
+
+var (
+	ErrInvalidPasswordReset  = newError(KindInvalid, "invalid_password_reset", "invalid or expired password reset token")
+	ErrTooManyPasswordResets = newError(KindTooManyRequests, "too_many_password_resets", "too many password reset emails, try again later")
+)
+
+const (
+	// passwordResetInterval is how long a user has to wait before getting another password reset email.
+	passwordResetInterval = time.Minute
+	// passwordResetsPerDay is how many password reset emails a user can get in a day.
+	passwordResetsPerDay = 5
+)
+
+// RequestPasswordReset emails a password reset token to the user with the given email. Unknown
+// emails are not reported, so the endpoint cannot be used to find out who has an account, and
+// the callers should not tell the errors apart either. A user only gets a few emails a day, and
+// not too often, so the endpoint cannot be used to flood an inbox.
+func (r *UserRepo) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := r.storage.UserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := r.now().UTC()
+	recent, err := r.storage.PasswordResets(user.ID, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if len(recent) >= passwordResetsPerDay || len(recent) > 0 && now.Sub(recent[0].CreatedAt) < passwordResetInterval {
+		return ErrTooManyPasswordResets
+	}
+
+	secret, err := newSecret("")
+	if err != nil {
+		return err
+	}
+	_, err = r.storage.SavePasswordReset(domain.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashToken(secret),
+		CreatedAt: now,
+		ExpiresAt: now.Add(domain.PasswordResetTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your gymlog password",
+		Body: fmt.Sprintf("Someone asked to reset the password of the gymlog account %s.\n\n"+
+			"Use this token in the next %v to choose a new password:\n\n%s\n\n"+
+			"If it was not you, ignore this email and your password will stay the same.",
+			user.Username, domain.PasswordResetTTL, secret),
+	})
+}
+
+// ResetPassword changes the password of the user of a reset token, which can only be used once,
+// logs them out of every session and revokes their access tokens.
+func (r *UserRepo) ResetPassword(secret string, passwordHash string) error {
+	reset, err := r.storage.PasswordReset(hashToken(secret))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidPasswordReset
+	}
+	if err != nil {
+		return err
+	}
+	now := r.now().UTC()
+	if !reset.IsUsable(now) {
+		return ErrInvalidPasswordReset
+	}
+
+	err = r.storage.ResetPassword(reset.ID, passwordHash, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another request used the token first.
+		return ErrInvalidPasswordReset
+	}
+	return err
+}
@@ -1,19 +1,115 @@
 package application
 
-import "gymlog/domain"
+import (
+	"context"
+	"gymlog/domain"
+	"time"
+)
 
 // RoutineRepository is the interface for the routine repository.
 type RoutineRepository interface {
-	Exercises() ([]domain.Exercise, error)
-	SetRoutine(userID int, routine domain.Routine) error
+	Exercises(userID int, query domain.ExerciseQuery) (domain.ExercisePage, error)
+	GetExercise(userID int, exerciseID int) (domain.Exercise, error)
+	CreateExercise(userID int, exercise domain.Exercise) (domain.Exercise, error)
+	UpdateExercise(userID int, exercise domain.Exercise) error
+	DeleteExercise(userID int, exerciseID int) error
+	SetRoutine(userID int, routine domain.Routine) (domain.Routine, error)
 	GetRoutines(userID int) ([]domain.Routine, error)
-	GetRoutine(routineID int) (domain.Routine, error)
+	GetRoutine(userID int, routineID int) (domain.Routine, error)
+	UpdateRoutine(userID int, routine domain.Routine) (domain.Routine, error)
+	PatchRoutine(userID int, routineID int, patch domain.RoutinePatch) (domain.Routine, error)
+	DeleteRoutine(userID int, routineID int) error
+	ShareRoutine(userID int, routineID int, username string) error
+	UnshareRoutine(userID int, routineID int, username string) error
+	StartWorkout(userID int, routineID *int) (domain.Workout, error)
+	AddWorkoutSet(userID int, workoutID int, set domain.WorkoutSet) (domain.Workout, error)
+	FinishWorkout(userID int, workoutID int) (domain.Workout, error)
+	GetWorkouts(userID int) ([]domain.Workout, error)
+	GetWorkout(userID int, workoutID int) (domain.Workout, error)
 }
 
+// UserRepository is the interface for the user repository, made of one interface per part of
+// the accounts so the code that only needs some of them can ask for less.
 type UserRepository interface {
+	AccountRepository
+	SessionRepository
+	LoginAttemptRepository
+	AccessTokenRepository
+	PasswordResetRepository
+	EmailVerificationRepository
+	MFARepository
+	DataExportRepository
+}
+
+// AccountRepository manages the accounts and their deletion.
+type AccountRepository interface {
 	Users(username string) ([]domain.User, error)
-	SaveUser(user domain.User) error
-	SaveSession(userID int, sessionToken string, csrfToken string) error
-	DeleteSession(userID int) error
-	UserSession(username string) (domain.UserSession, error)
+	User(userID int) (domain.User, error)
+	SaveUser(user domain.User) (int, error)
+	ChangePassword(userID int, sessionID int, passwordHash string) error
+	ChangeEmail(userID int, email string) error
+	ScheduleAccountDeletion(userID int) (time.Time, error)
+	CancelAccountDeletion(userID int) error
+	DeleteScheduledAccounts() (int, error)
+}
+
+// SessionRepository manages the logged in sessions.
+type SessionRepository interface {
+	SaveSession(session domain.UserSession) (domain.UserSession, error)
+	Session(sessionToken string) (domain.UserSession, error)
+	TouchSession(session domain.UserSession, ip string) error
+	Sessions(userID int) ([]domain.UserSession, error)
+	DeleteSession(userID int, sessionID int) error
+	DeleteOtherSessions(userID int, sessionID int) error
+	DeleteExpiredSessions() (int, error)
+	SessionPolicy() domain.SessionPolicy
+}
+
+// LoginAttemptRepository records the login attempts and computes the lockouts.
+type LoginAttemptRepository interface {
+	LoginDelay(username, ip string) (time.Duration, error)
+	RecordLoginAttempt(username, ip string, outcome domain.LoginOutcome) error
+	DeleteOldLoginAttempts() (int, error)
+}
+
+// AccessTokenRepository manages the personal access tokens.
+type AccessTokenRepository interface {
+	CreateAccessToken(userID int, token domain.AccessToken) (domain.AccessToken, string, error)
+	AccessToken(secret string) (domain.AccessToken, error)
+	TouchAccessToken(token domain.AccessToken) error
+	AccessTokens(userID int) ([]domain.AccessToken, error)
+	AccessTokenByID(userID int, tokenID int) (domain.AccessToken, error)
+	DeleteAccessToken(userID int, tokenID int) error
+}
+
+// PasswordResetRepository resets forgotten passwords.
+type PasswordResetRepository interface {
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(secret string, passwordHash string) error
+}
+
+// EmailVerificationRepository verifies the emails of the accounts.
+type EmailVerificationRepository interface {
+	SendEmailVerification(ctx context.Context, userID int) error
+	VerifyEmail(secret string) error
+}
+
+// MFARepository manages the two-factor authentication and its login challenges.
+type MFARepository interface {
+	EnrollTOTP(userID int) (string, string, error)
+	ConfirmTOTP(userID int, code string) ([]string, error)
+	TOTPEnabled(userID int) (bool, error)
+	DisableTOTP(userID int) error
+	StartMFAChallenge(userID int) (string, error)
+	CompleteMFAChallenge(secret string, code string) (int, error)
+}
+
+// DataExportRepository builds and serves the exports of the data of the users.
+type DataExportRepository interface {
+	RequestDataExport(userID int) (domain.DataExport, error)
+	DataExports(userID int) ([]domain.DataExport, error)
+	DataExport(userID int, exportID int) (domain.DataExport, error)
+	DataExportArchive(userID int, exportID int) ([]byte, error)
+	BuildDataExports() (int, error)
+	DeleteExpiredDataExports() (int, error)
 }
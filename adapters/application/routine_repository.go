@@ -1,35 +1,44 @@
 package application
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"gymlog/adapters/storage"
 	"gymlog/domain"
+	"time"
+)
+
+var (
+	ErrRoutineNotFound = newError(KindNotFound, "routine_not_found", "routine not found")
+	ErrInvalidRoutine  = newError(KindInvalid, "invalid_routine", "invalid routine")
+	ErrShareWithSelf   = newError(KindInvalid, "share_with_self", "cannot share a routine with yourself")
 )
 
 // GymRepository is in charge of application business logic.
 type GymRepository struct {
 	storage storage.Storage
+	now     func() time.Time
 }
 
 // NewGymRepository is the constructor for the GymRepository.
 func NewGymRepository(storage storage.Storage) RoutineRepository {
-	return &GymRepository{storage: storage}
-}
-
-// Exercises returns all the exercises from the storage.
-func (r *GymRepository) Exercises() ([]domain.Exercise, error) {
-	exercises, err := r.storage.Exercises()
-	if err != nil {
-		return nil, err
-	}
-	return exercises, nil
+	return &GymRepository{storage: storage, now: time.Now}
 }
 
-func (r *GymRepository) SetRoutine(userID int, routine domain.Routine) error {
+// SetRoutine saves a new routine for the user and returns it as stored, with its ID.
+func (r *GymRepository) SetRoutine(userID int, routine domain.Routine) (domain.Routine, error) {
 	if len(routine.Exercises) == 0 {
-		return errors.New("routine must have at least one exercise")
+		return domain.Routine{}, fmt.Errorf("%w: %w", ErrInvalidRoutine, &domain.ValidationError{Field: "exercises", Message: "routine must have at least one exercise"})
+	}
+	if err := r.checkRoutineExercises(userID, routine); err != nil {
+		return domain.Routine{}, err
+	}
+	routineID, err := r.storage.SaveRoutine(userID, routine)
+	if err != nil {
+		return domain.Routine{}, err
 	}
-	return r.storage.SaveRoutine(userID, routine)
+	return r.GetRoutine(userID, routineID)
 }
 
 func (r *GymRepository) GetRoutines(userID int) ([]domain.Routine, error) {
@@ -40,10 +49,139 @@ func (r *GymRepository) GetRoutines(userID int) ([]domain.Routine, error) {
 	return routines, nil
 }
 
-func (r *GymRepository) GetRoutine(routineID int) (domain.Routine, error) {
-	routine, err := r.storage.Routine(routineID)
+// GetRoutine returns a routine owned by or shared with the user.
+func (r *GymRepository) GetRoutine(userID int, routineID int) (domain.Routine, error) {
+	routine, err := r.storage.Routine(userID, routineID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Routine{}, ErrRoutineNotFound
+	}
 	if err != nil {
 		return domain.Routine{}, err
 	}
 	return routine, nil
 }
+
+// UpdateRoutine replaces a routine owned by the user with the given one and returns it as stored.
+func (r *GymRepository) UpdateRoutine(userID int, routine domain.Routine) (domain.Routine, error) {
+	if _, err := r.ownedRoutine(userID, routine.ID); err != nil {
+		return domain.Routine{}, err
+	}
+	if err := r.updateOwnedRoutine(userID, routine); err != nil {
+		return domain.Routine{}, err
+	}
+	return r.GetRoutine(userID, routine.ID)
+}
+
+// PatchRoutine applies a partial update to a routine owned by the user and returns it as stored.
+func (r *GymRepository) PatchRoutine(userID int, routineID int, patch domain.RoutinePatch) (domain.Routine, error) {
+	routine, err := r.ownedRoutine(userID, routineID)
+	if err != nil {
+		return domain.Routine{}, err
+	}
+
+	patched, err := routine.Apply(patch)
+	if err != nil {
+		return domain.Routine{}, fmt.Errorf("%w: %w", ErrInvalidRoutine, err)
+	}
+	if err := r.updateOwnedRoutine(userID, patched); err != nil {
+		return domain.Routine{}, err
+	}
+	return r.GetRoutine(userID, routineID)
+}
+
+// ownedRoutine returns a routine owned by the user. Routines shared with the user are reported as
+// not found before validating any change, so the errors do not tell anything about them.
+func (r *GymRepository) ownedRoutine(userID int, routineID int) (domain.Routine, error) {
+	routine, err := r.GetRoutine(userID, routineID)
+	if err != nil {
+		return domain.Routine{}, err
+	}
+	if routine.UserID != userID {
+		return domain.Routine{}, ErrRoutineNotFound
+	}
+	return routine, nil
+}
+
+func (r *GymRepository) updateOwnedRoutine(userID int, routine domain.Routine) error {
+	if len(routine.Exercises) == 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidRoutine, &domain.ValidationError{Field: "exercises", Message: "routine must have at least one exercise"})
+	}
+	if err := r.checkRoutineExercises(userID, routine); err != nil {
+		return err
+	}
+	// The storage filters by owner too, in case the routine was deleted meanwhile.
+	err := r.storage.UpdateRoutine(userID, routine)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRoutineNotFound
+	}
+	return err
+}
+
+func (r *GymRepository) DeleteRoutine(userID int, routineID int) error {
+	err := r.storage.DeleteRoutine(userID, routineID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRoutineNotFound
+	}
+	return err
+}
+
+// checkRoutineExercises makes sure every exercise of the routine is global or was created by the user.
+func (r *GymRepository) checkRoutineExercises(userID int, routine domain.Routine) error {
+	for _, exercise := range routine.Exercises {
+		if _, err := r.GetExercise(userID, exercise.ID); err != nil {
+			if errors.Is(err, ErrExerciseNotFound) {
+				message := fmt.Sprintf("exercise %d does not exist", exercise.ID)
+				return fmt.Errorf("%w: %w", ErrInvalidRoutine, &domain.ValidationError{Field: "exercises", Message: message})
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// ShareRoutine gives read access to a routine of the user to another user. Sharing with a username
+// that does not exist does nothing and succeeds, so the answer does not tell which users exist.
+func (r *GymRepository) ShareRoutine(userID int, routineID int, username string) error {
+	sharedWith, found, err := r.shareTarget(userID, routineID, username)
+	if err != nil || !found {
+		return err
+	}
+	err = r.storage.ShareRoutine(userID, routineID, sharedWith.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRoutineNotFound
+	}
+	return err
+}
+
+// UnshareRoutine takes the read access to a routine of the user away from another user, it
+// succeeds whether or not the routine was shared with them, or they exist.
+func (r *GymRepository) UnshareRoutine(userID int, routineID int, username string) error {
+	sharedWith, found, err := r.shareTarget(userID, routineID, username)
+	if err != nil || !found {
+		return err
+	}
+	err = r.storage.UnshareRoutine(userID, routineID, sharedWith.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// shareTarget checks that the routine belongs to the user and finds the user to share it with,
+// found is false when there is no user with that username.
+func (r *GymRepository) shareTarget(userID int, routineID int, username string) (domain.User, bool, error) {
+	if _, err := r.ownedRoutine(userID, routineID); err != nil {
+		return domain.User{}, false, err
+	}
+	users, err := r.storage.Users(username)
+	if err != nil {
+		return domain.User{}, false, err
+	}
+	if len(users) == 0 {
+		return domain.User{}, false, nil
+	}
+	if users[0].ID == userID {
+		return domain.User{}, false, ErrShareWithSelf
+	}
+	return users[0], true, nil
+}
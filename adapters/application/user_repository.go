@@ -1,17 +1,54 @@
 package application
 
 import (
+	"database/sql"
 	"errors"
+	"gymlog/adapters/mailer"
 	"gymlog/adapters/storage"
 	"gymlog/domain"
+	"slices"
+	"time"
+)
+
+var (
+	ErrUserNotFound    = newError(KindNotFound, "user_not_found", "user not found")
+	ErrSessionNotFound = newError(KindNotFound, "session_not_found", "session not found")
+	ErrUsernameTaken   = newError(KindConflict, "username_taken", "username is already taken")
+	ErrEmailTaken      = newError(KindConflict, "email_taken", "email is already taken")
+)
+
+const (
+	// sessionTouchInterval is how often the last seen time of a session is written, so every
+	// authenticated request does not turn into a write.
+	sessionTouchInterval = time.Minute
+	// maxDeviceNameLength and maxUserAgentLength are the lengths of the session columns.
+	maxDeviceNameLength = 255
+	maxUserAgentLength  = 512
 )
 
 type UserRepo struct {
-	storage storage.Storage
+	storage        storage.Storage
+	policy         domain.SessionPolicy
+	accountLockout domain.LockoutPolicy
+	ipLockout      domain.LockoutPolicy
+	mailer         mailer.Mailer
+	now            func() time.Time
 }
 
-func NewUserRepo(storage storage.Storage) UserRepository {
-	return &UserRepo{storage: storage}
+func NewUserRepo(storage storage.Storage, policy domain.SessionPolicy, mailer mailer.Mailer) UserRepository {
+	return &UserRepo{
+		storage:        storage,
+		policy:         policy,
+		accountLockout: domain.DefaultAccountLockout,
+		ipLockout:      domain.DefaultIPLockout,
+		mailer:         mailer,
+		now:            time.Now,
+	}
+}
+
+// SessionPolicy returns how long the sessions of the repository last.
+func (r *UserRepo) SessionPolicy() domain.SessionPolicy {
+	return r.policy
 }
 
 func (r *UserRepo) Users(username string) ([]domain.User, error) {
@@ -22,25 +59,104 @@ func (r *UserRepo) Users(username string) ([]domain.User, error) {
 	return users, nil
 }
 
-func (r *UserRepo) UserSession(username string) (domain.UserSession, error) {
-	user, err := r.Users(username)
+// User returns the user with the given ID.
+func (r *UserRepo) User(userID int) (domain.User, error) {
+	user, err := r.storage.User(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, ErrUserNotFound
+	}
+	return user, err
+}
+
+// SaveUser saves a new user and returns its ID, the username and email cannot belong to another
+// user, ignoring case.
+func (r *UserRepo) SaveUser(user domain.User) (int, error) {
+	userID, err := r.storage.SaveUser(user.Username, user.Email, user.PasswordHash)
+	if errors.Is(err, storage.ErrUsernameTaken) {
+		return 0, ErrUsernameTaken
+	}
+	if errors.Is(err, storage.ErrEmailTaken) {
+		return 0, ErrEmailTaken
+	}
+	return userID, err
+}
+
+// SaveSession starts a new session for a device, the other sessions of the user stay active.
+// Device names and user agents longer than their columns are cut.
+func (r *UserRepo) SaveSession(session domain.UserSession) (domain.UserSession, error) {
+	session.DeviceName = truncate(session.DeviceName, maxDeviceNameLength)
+	session.UserAgent = truncate(session.UserAgent, maxUserAgentLength)
+	now := r.now().UTC()
+	session.CreatedAt = now
+	session.LastSeenAt = now
+	session.ExpiresAt = r.policy.ExpiresAt(now, now)
+
+	sessionID, err := r.storage.SaveSession(session)
+	if err != nil {
+		return domain.UserSession{}, err
+	}
+	session.ID = sessionID
+	return session, nil
+}
+
+// Session returns the session of a session token, expired sessions are deleted and reported
+// as not found.
+func (r *UserRepo) Session(sessionToken string) (domain.UserSession, error) {
+	session, err := r.storage.Session(sessionToken)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.UserSession{}, ErrSessionNotFound
+	}
 	if err != nil {
 		return domain.UserSession{}, err
 	}
-	if len(user) == 0 {
-		return domain.UserSession{}, errors.New("user not found")
+	if session.IsExpired(r.now()) {
+		if err := r.storage.DeleteSession(session.UserID, session.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return domain.UserSession{}, err
+		}
+		return domain.UserSession{}, ErrSessionNotFound
 	}
-	return r.storage.GetUserSession(user[0].ID)
+	return session, nil
 }
 
-func (r *UserRepo) SaveUser(user domain.User) error {
-	return r.storage.SaveUser(user.Username, user.Email, user.PasswordHash)
+// TouchSession records that the session was just used from the given IP and renews it for
+// another idle timeout, up to the max lifetime of the session.
+func (r *UserRepo) TouchSession(session domain.UserSession, ip string) error {
+	now := r.now().UTC()
+	// With short idle timeouts the session has to be renewed more often than once a minute.
+	touchInterval := min(sessionTouchInterval, r.policy.IdleTimeout/10)
+	if now.Sub(session.LastSeenAt) < touchInterval && session.IP == ip {
+		return nil
+	}
+	return r.storage.TouchSession(session.ID, now, r.policy.ExpiresAt(session.CreatedAt, now), ip)
+}
+
+// Sessions returns the active sessions of the user, the most recently used first.
+func (r *UserRepo) Sessions(userID int) ([]domain.UserSession, error) {
+	sessions, err := r.storage.Sessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := r.now()
+	return slices.DeleteFunc(sessions, func(session domain.UserSession) bool {
+		return session.IsExpired(now)
+	}), nil
+}
+
+// DeleteSession logs out one of the sessions of the user.
+func (r *UserRepo) DeleteSession(userID int, sessionID int) error {
+	err := r.storage.DeleteSession(userID, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSessionNotFound
+	}
+	return err
 }
 
-func (r *UserRepo) SaveSession(userID int, sessionToken string, csrfToken string) error {
-	return r.storage.SaveSession(userID, sessionToken, csrfToken)
+// DeleteOtherSessions logs out every session of the user except the given one.
+func (r *UserRepo) DeleteOtherSessions(userID int, sessionID int) error {
+	return r.storage.DeleteOtherSessions(userID, sessionID)
 }
 
-func (r *UserRepo) DeleteSession(userID int) error {
-	return r.storage.DeleteSession(userID)
+// DeleteExpiredSessions deletes every expired session and returns how many were deleted.
+func (r *UserRepo) DeleteExpiredSessions() (int, error) {
+	return r.storage.DeleteExpiredSessions(r.now().UTC())
 }
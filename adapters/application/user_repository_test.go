@@ -0,0 +1,380 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"gymlog/adapters/mailer"
+	"gymlog/adapters/storage"
+	"gymlog/domain"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestUserRepo returns a user repository backed by a fresh in-memory storage whose clock
+// only moves when the test advances it.
+func newTestUserRepo(t *testing.T, policy domain.SessionPolicy) (*UserRepo, *time.Time) {
+	t.Helper()
+
+	store, err := storage.NewMemoryStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewUserRepo(store, policy, mailer.NewLogMailer(io.Discard)).(*UserRepo)
+	repo.now = func() time.Time { return now }
+	if _, err := repo.SaveUser(domain.User{Username: "alice", Email: "alice@gymlog.test", PasswordHash: "hash"}); err != nil {
+		t.Fatal(err)
+	}
+	return repo, &now
+}
+
+func TestSessionSlidingExpiry(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.SessionPolicy{IdleTimeout: time.Hour, MaxLifetime: 3 * time.Hour})
+	session, err := repo.SaveSession(domain.UserSession{UserID: 1, SessionToken: "token", CSRFToken: "csrf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !session.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("session expires at %v, want an hour after the login", session.ExpiresAt)
+	}
+
+	// Using the session every 50 minutes keeps it alive past the idle timeout...
+	for range 3 {
+		*now = now.Add(50 * time.Minute)
+		session, err = repo.Session("token")
+		if err != nil {
+			t.Fatalf("active session after %v: %v", now.Sub(session.CreatedAt), err)
+		}
+		if err := repo.TouchSession(session, "10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// ...but not past the max lifetime.
+	*now = now.Add(40 * time.Minute)
+	if _, err := repo.Session("token"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("session older than the max lifetime: got %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionDeviceIsCut(t *testing.T) {
+	repo, _ := newTestUserRepo(t, domain.DefaultSessionPolicy)
+	name := strings.Repeat("é", maxDeviceNameLength+10)
+	session, err := repo.SaveSession(domain.UserSession{UserID: 1, SessionToken: "token", CSRFToken: "csrf", DeviceName: name, UserAgent: name + name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The columns count characters, so a name of two byte characters keeps all 255 of them.
+	if session.DeviceName != strings.Repeat("é", maxDeviceNameLength) || session.UserAgent != strings.Repeat("é", maxUserAgentLength) {
+		t.Fatalf("got device %q and user agent %q", session.DeviceName, session.UserAgent)
+	}
+}
+
+func TestSessionIdleExpiry(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.SessionPolicy{IdleTimeout: time.Hour, MaxLifetime: 3 * time.Hour})
+	if _, err := repo.SaveSession(domain.UserSession{UserID: 1, SessionToken: "token", CSRFToken: "csrf"}); err != nil {
+		t.Fatal(err)
+	}
+
+	*now = now.Add(time.Hour)
+	if _, err := repo.Session("token"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("idle session: got %v, want ErrSessionNotFound", err)
+	}
+	if sessions, err := repo.Sessions(1); err != nil || len(sessions) != 0 {
+		t.Fatalf("got sessions %v, %v after the session expired", sessions, err)
+	}
+}
+
+func TestDeleteExpiredSessions(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.SessionPolicy{IdleTimeout: time.Hour, MaxLifetime: 3 * time.Hour})
+	if _, err := repo.SaveSession(domain.UserSession{UserID: 1, SessionToken: "old", CSRFToken: "csrf"}); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(30 * time.Minute)
+	if _, err := repo.SaveSession(domain.UserSession{UserID: 1, SessionToken: "new", CSRFToken: "csrf"}); err != nil {
+		t.Fatal(err)
+	}
+
+	*now = now.Add(45 * time.Minute)
+	deleted, err := repo.DeleteExpiredSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Fatalf("deleted %d sessions, want 1", deleted)
+	}
+	if sessions, err := repo.storage.Sessions(1); err != nil || len(sessions) != 1 || sessions[0].SessionToken != "new" {
+		t.Fatalf("got sessions %v, %v", sessions, err)
+	}
+}
+
+func TestAccessTokenExpiry(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.DefaultSessionPolicy)
+	expiresAt := now.Add(time.Hour)
+	token, secret, err := repo.CreateAccessToken(1, domain.AccessToken{Name: "cron", Scope: domain.ScopeRead, ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.TokenHash == secret || token.TokenHash != hashToken(secret) {
+		t.Fatalf("token is not stored hashed: %+v", token)
+	}
+
+	if found, err := repo.AccessToken(secret); err != nil || found.ID != token.ID {
+		t.Fatalf("got %+v, %v", found, err)
+	}
+	if _, err := repo.AccessToken(secret + "x"); !errors.Is(err, ErrAccessTokenNotFound) {
+		t.Fatalf("wrong secret: got %v, want ErrAccessTokenNotFound", err)
+	}
+	*now = now.Add(time.Hour)
+	if _, err := repo.AccessToken(secret); !errors.Is(err, ErrAccessTokenNotFound) {
+		t.Fatalf("expired token: got %v, want ErrAccessTokenNotFound", err)
+	}
+}
+
+func TestPasswordResetExpiry(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.DefaultSessionPolicy)
+	mails := mailer.NewLogMailer(io.Discard)
+	repo.mailer = mails
+
+	if err := repo.RequestPasswordReset(context.Background(), "alice@gymlog.test"); err != nil {
+		t.Fatal(err)
+	}
+	if len(mails.Messages()) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(mails.Messages()))
+	}
+	reset, err := repo.storage.PasswordReset(hashToken(lastLine(mails.Messages()[0].Body, 2)))
+	if err != nil {
+		t.Fatalf("the emailed token is not stored hashed: %v", err)
+	}
+
+	*now = now.Add(domain.PasswordResetTTL)
+	secret := lastLine(mails.Messages()[0].Body, 2)
+	if err := repo.ResetPassword(secret, "new-hash"); !errors.Is(err, ErrInvalidPasswordReset) {
+		t.Fatalf("expired reset: got %v, want ErrInvalidPasswordReset", err)
+	}
+	if user, err := repo.User(reset.UserID); err != nil || user.PasswordHash != "hash" {
+		t.Fatalf("expired reset changed the password: %+v, %v", user, err)
+	}
+}
+
+func TestPasswordResetRateLimit(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.DefaultSessionPolicy)
+	mails := mailer.NewLogMailer(io.Discard)
+	repo.mailer = mails
+	ctx := context.Background()
+
+	if err := repo.RequestPasswordReset(ctx, "alice@gymlog.test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RequestPasswordReset(ctx, "alice@gymlog.test"); !errors.Is(err, ErrTooManyPasswordResets) {
+		t.Fatalf("ask again right away: got %v, want ErrTooManyPasswordResets", err)
+	}
+	for range passwordResetsPerDay - 1 {
+		*now = now.Add(passwordResetInterval)
+		if err := repo.RequestPasswordReset(ctx, "alice@gymlog.test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	*now = now.Add(passwordResetInterval)
+	if err := repo.RequestPasswordReset(ctx, "alice@gymlog.test"); !errors.Is(err, ErrTooManyPasswordResets) {
+		t.Fatalf("over the daily limit: got %v, want ErrTooManyPasswordResets", err)
+	}
+	if len(mails.Messages()) != passwordResetsPerDay {
+		t.Fatalf("sent %d emails, want %d", len(mails.Messages()), passwordResetsPerDay)
+	}
+
+	*now = now.Add(24 * time.Hour)
+	if err := repo.RequestPasswordReset(ctx, "alice@gymlog.test"); err != nil {
+		t.Fatalf("a day later: %v", err)
+	}
+}
+
+// lastLine returns the nth line of the text counting from the end, the last one is 0.
+func lastLine(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	return lines[len(lines)-1-n]
+}
+
+func TestEmailVerificationRateLimit(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.DefaultSessionPolicy)
+	ctx := context.Background()
+
+	if err := repo.SendEmailVerification(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SendEmailVerification(ctx, 1); !errors.Is(err, ErrTooManyEmailVerifications) {
+		t.Fatalf("resend right away: got %v, want ErrTooManyEmailVerifications", err)
+	}
+	for range emailVerificationsPerDay - 1 {
+		*now = now.Add(emailVerificationInterval)
+		if err := repo.SendEmailVerification(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	*now = now.Add(time.Hour)
+	if err := repo.SendEmailVerification(ctx, 1); !errors.Is(err, ErrTooManyEmailVerifications) {
+		t.Fatalf("resend past the daily limit: got %v, want ErrTooManyEmailVerifications", err)
+	}
+	*now = now.Add(24 * time.Hour)
+	if err := repo.SendEmailVerification(ctx, 1); err != nil {
+		t.Fatalf("resend the next day: %v", err)
+	}
+}
+
+func TestLoginDelay(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.DefaultSessionPolicy)
+	repo.accountLockout = domain.LockoutPolicy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: 4 * time.Minute, Window: time.Hour}
+	repo.ipLockout = domain.LockoutPolicy{FreeAttempts: 4, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+
+	fail := func(username, ip string) {
+		t.Helper()
+		if err := repo.RecordLoginAttempt(username, ip, domain.LoginFailed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantDelay := func(username, ip string, want time.Duration) {
+		t.Helper()
+		delay, err := repo.LoginDelay(username, ip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if delay != want {
+			t.Fatalf("delay of %s from %s: got %v, want %v", username, ip, delay, want)
+		}
+	}
+
+	fail("alice", "10.0.0.1")
+	wantDelay("alice", "10.0.0.1", 0)
+	fail("alice", "10.0.0.1")
+	wantDelay("alice", "10.0.0.1", time.Minute)
+
+	// Every failure after the wait doubles it, up to the maximum.
+	wait := time.Minute
+	for _, want := range []time.Duration{2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		*now = now.Add(wait)
+		fail("alice", "10.0.0.2")
+		wantDelay("alice", "10.0.0.3", want)
+		wait = want
+	}
+
+	// A successful login starts over.
+	*now = now.Add(time.Hour)
+	if err := repo.RecordLoginAttempt("alice", "10.0.0.1", domain.LoginSucceeded); err != nil {
+		t.Fatal(err)
+	}
+	fail("alice", "10.0.0.1")
+	wantDelay("alice", "10.0.0.1", 0)
+
+	// Guessing the passwords of many users from one IP locks the IP.
+	for _, username := range []string{"bob", "carol", "dave", "frank"} {
+		fail(username, "10.0.0.9")
+	}
+	wantDelay("erin", "10.0.0.9", time.Minute)
+	wantDelay("erin", "10.0.0.8", 0)
+}
+
+func TestAccountDeletionGracePeriod(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.DefaultSessionPolicy)
+
+	deleteAt, err := repo.ScheduleAccountDeletion(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(domain.AccountDeletionGracePeriod); !deleteAt.Equal(want) {
+		t.Fatalf("deletion scheduled at %v, want %v", deleteAt, want)
+	}
+	if _, err := repo.ScheduleAccountDeletion(1); !errors.Is(err, ErrAccountDeletionPending) {
+		t.Fatalf("schedule twice: got %v, want ErrAccountDeletionPending", err)
+	}
+
+	*now = deleteAt.Add(-time.Second)
+	if deleted, err := repo.DeleteScheduledAccounts(); err != nil || deleted != 0 {
+		t.Fatalf("within the grace period: deleted %d, %v", deleted, err)
+	}
+	if err := repo.CancelAccountDeletion(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CancelAccountDeletion(1); !errors.Is(err, ErrNoAccountDeletion) {
+		t.Fatalf("cancel twice: got %v, want ErrNoAccountDeletion", err)
+	}
+	*now = deleteAt
+	if deleted, err := repo.DeleteScheduledAccounts(); err != nil || deleted != 0 {
+		t.Fatalf("cancelled deletion: deleted %d, %v", deleted, err)
+	}
+
+	deleteAt, err = repo.ScheduleAccountDeletion(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*now = deleteAt
+	if deleted, err := repo.DeleteScheduledAccounts(); err != nil || deleted != 1 {
+		t.Fatalf("after the grace period: deleted %d, %v", deleted, err)
+	}
+	if _, err := repo.User(1); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("deleted user: got %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestDataExportExpiry(t *testing.T) {
+	repo, now := newTestUserRepo(t, domain.DefaultSessionPolicy)
+
+	export, err := repo.RequestDataExport(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.DataExportArchive(1, export.ID); !errors.Is(err, ErrDataExportNotReady) {
+		t.Fatalf("pending export: got %v, want ErrDataExportNotReady", err)
+	}
+	if built, err := repo.BuildDataExports(); err != nil || built != 1 {
+		t.Fatalf("built %d exports, %v", built, err)
+	}
+	if archive, err := repo.DataExportArchive(1, export.ID); err != nil || len(archive) == 0 {
+		t.Fatalf("ready export: got %d bytes, %v", len(archive), err)
+	}
+
+	*now = now.Add(domain.DataExportTTL)
+	if _, err := repo.DataExportArchive(1, export.ID); !errors.Is(err, ErrDataExportExpired) {
+		t.Fatalf("expired export: got %v, want ErrDataExportExpired", err)
+	}
+	if exports, err := repo.DataExports(1); err != nil || len(exports) != 0 {
+		t.Fatalf("expired exports are listed: %+v, %v", exports, err)
+	}
+	if deleted, err := repo.DeleteExpiredDataExports(); err != nil || deleted != 1 {
+		t.Fatalf("deleted %d expired exports, %v", deleted, err)
+	}
+	if _, err := repo.DataExportArchive(1, export.ID); !errors.Is(err, ErrDataExportNotFound) {
+		t.Fatalf("deleted export: got %v, want ErrDataExportNotFound", err)
+	}
+}
+
+func TestDataExportsAreBuiltOnce(t *testing.T) {
+	repo, _ := newTestUserRepo(t, domain.DefaultSessionPolicy)
+
+	if _, err := repo.RequestDataExport(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two builders running at once, each export is claimed by one of them.
+	var wg sync.WaitGroup
+	var built atomic.Int64
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := repo.BuildDataExports()
+			if err != nil {
+				t.Error(err)
+			}
+			built.Add(int64(n))
+		}()
+	}
+	wg.Wait()
+	if built.Load() != 1 {
+		t.Fatalf("built %d exports, want 1", built.Load())
+	}
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"gymlog/domain"
+)
+
+var (
+	ErrWorkoutNotFound = newError(KindNotFound, "workout_not_found", "workout not found")
+	ErrWorkoutFinished = newError(KindConflict, "workout_finished", "workout already finished")
+)
+
+// StartWorkout opens a new workout for the user, optionally based on one of their routines.
+func (r *GymRepository) StartWorkout(userID int, routineID *int) (domain.Workout, error) {
+	if routineID != nil {
+		_, err := r.storage.Routine(userID, *routineID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Workout{}, ErrRoutineNotFound
+		}
+		if err != nil {
+			return domain.Workout{}, err
+		}
+	}
+	workout := domain.StartWorkout(routineID, r.now())
+	workoutID, err := r.storage.SaveWorkout(userID, workout)
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	workout.ID = workoutID
+	return workout, nil
+}
+
+// AddWorkoutSet logs a performed set into a workout that is still in progress.
+func (r *GymRepository) AddWorkoutSet(userID int, workoutID int, set domain.WorkoutSet) (domain.Workout, error) {
+	workout, err := r.GetWorkout(userID, workoutID)
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	if workout.IsFinished() {
+		return domain.Workout{}, ErrWorkoutFinished
+	}
+	if _, err := r.GetExercise(userID, set.ExerciseID); err != nil {
+		return domain.Workout{}, err
+	}
+	err = r.storage.SaveWorkoutSet(workoutID, set)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The workout was finished after it was read.
+		return domain.Workout{}, ErrWorkoutFinished
+	}
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	return r.GetWorkout(userID, workoutID)
+}
+
+func (r *GymRepository) FinishWorkout(userID int, workoutID int) (domain.Workout, error) {
+	workout, err := r.GetWorkout(userID, workoutID)
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	if workout.IsFinished() {
+		return domain.Workout{}, ErrWorkoutFinished
+	}
+	err = r.storage.FinishWorkout(userID, workoutID, r.now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another request finished it first.
+		return domain.Workout{}, ErrWorkoutFinished
+	}
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	return r.GetWorkout(userID, workoutID)
+}
+
+func (r *GymRepository) GetWorkouts(userID int) ([]domain.Workout, error) {
+	workouts, err := r.storage.Workouts(userID)
+	if err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+func (r *GymRepository) GetWorkout(userID int, workoutID int) (domain.Workout, error) {
+	workout, err := r.storage.Workout(userID, workoutID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Workout{}, ErrWorkoutNotFound
+	}
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	return workout, nil
+}
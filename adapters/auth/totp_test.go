@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// SHA1 test vectors of RFC 6238 appendix B, which have 8 digits, the 6 digit codes are their end.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := tt.want[len(tt.want)-TOTPDigits:]; code != want {
+			t.Fatalf("code at %d: got %s, want %s", tt.unix, code, want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	step := TOTPStep(now)
+
+	for _, offset := range []int64{-1, 0, 1} {
+		code, err := TOTPCode(secret, step+offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := ValidateTOTP(secret, code, now); !ok || got != step+offset {
+			t.Fatalf("code of step %+d: got step %d, %v", offset, got, ok)
+		}
+	}
+	old, err := TOTPCode(secret, step-2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ValidateTOTP(secret, old, now); ok {
+		t.Fatal("a code from a minute ago is valid")
+	}
+	if _, ok := ValidateTOTP(secret, "12345", now); ok {
+		t.Fatal("a short code is valid")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("gymlog", "alice smith", "JBSWY3DPEHPK3PXP")
+	for _, part := range []string{"otpauth://totp/gymlog:alice%20smith?", "secret=JBSWY3DPEHPK3PXP", "issuer=gymlog", "digits=6", "period=30"} {
+		if !strings.Contains(uri, part) {
+			t.Fatalf("URI %s does not contain %s", uri, part)
+		}
+	}
+}
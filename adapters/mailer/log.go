@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// logMailerKeeps is how many of the last emails a LogMailer remembers for Messages.
+const logMailerKeeps = 100
+
+// LogMailer writes the emails to a writer instead of sending them, to use the emails locally
+// without an SMTP server. Tests can read them back with Messages.
+type LogMailer struct {
+	mu       sync.Mutex
+	w        io.Writer
+	messages []Message
+}
+
+// NewLogMailer creates a mailer that writes the emails to w, like os.Stdout or a file.
+func NewLogMailer(w io.Writer) *LogMailer {
+	return &LogMailer{w: w}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, message)
+	if len(m.messages) > logMailerKeeps {
+		m.messages = m.messages[len(m.messages)-logMailerKeeps:]
+	}
+	_, err := fmt.Fprintf(m.w, "To: %s\nSubject: %s\n\n%s\n\n", message.To, message.Subject, message.Body)
+	return err
+}
+
+// Messages returns the last emails sent, the oldest first.
+func (m *LogMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
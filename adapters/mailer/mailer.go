@@ -0,0 +1,15 @@
+package mailer
+
+import "context"
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails to the users, like the password reset links.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLogMailer(t *testing.T) {
+	var out strings.Builder
+	m := NewLogMailer(&out)
+
+	message := Message{To: "alice@gymlog.test", Subject: "Hi", Body: "Leg day"}
+	if err := m.Send(context.Background(), message); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Messages(); len(got) != 1 || got[0] != message {
+		t.Fatalf("got messages %+v", got)
+	}
+	if !strings.Contains(out.String(), "To: alice@gymlog.test") || !strings.Contains(out.String(), "Leg day") {
+		t.Fatalf("got output %q", out.String())
+	}
+}
+
+func TestSMTPMailerFormat(t *testing.T) {
+	m, err := NewSMTPMailer("smtp.gymlog.test:587", "", "", "gymlog@gymlog.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := string(m.(*smtpMailer).format(Message{To: "alice@gymlog.test", Subject: "Hi", Body: "one\ntwo"}))
+	for _, want := range []string{"From: gymlog@gymlog.test\r\n", "To: alice@gymlog.test\r\n", "Subject: Hi\r\n", "\r\n\r\none\r\ntwo"} {
+		if !strings.Contains(raw, want) {
+			t.Fatalf("email %q does not contain %q", raw, want)
+		}
+	}
+
+	if _, err := NewSMTPMailer("no-port", "", "", "gymlog@gymlog.test"); err == nil {
+		t.Fatal("accepted an SMTP address without port")
+	}
+}
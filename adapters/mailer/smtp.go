@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// smtpMailer sends the emails through an SMTP server, with STARTTLS when the server offers it.
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer creates a mailer for the SMTP server at addr ("host:port"). Without username the
+// server is used without authentication.
+func NewSMTPMailer(addr, username, password, from string) (Mailer, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", addr, err)
+	}
+	if from == "" {
+		return nil, errors.New("the sender address of the emails is required")
+	}
+
+	m := &smtpMailer{addr: addr, from: from}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m, nil
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message Message) error {
+	// A line break would let the recipient or subject add their own headers.
+	if strings.ContainsAny(message.To+message.Subject, "\r\n") {
+		return errors.New("email recipient and subject cannot contain line breaks")
+	}
+
+	// smtp.SendMail does not take a context, so it runs in the background and is abandoned when
+	// the context is done.
+	result := make(chan error, 1)
+	go func() {
+		result <- smtp.SendMail(m.addr, m.auth, m.from, []string{message.To}, m.format(message))
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// format builds the raw email with its headers.
+func (m *smtpMailer) format(message Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
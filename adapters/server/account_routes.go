@@ -0,0 +1,102 @@
+package server
+
+import (
+	"gymlog/domain"
+	"log"
+	"net/http"
+	"time"
+)
+
+type accountDeletionResponse struct {
+	DeleteAt time.Time `json:"delete_at"`
+}
+
+// handleGetMe returns the account of the authenticated user.
+func (s *gymlogServer) handleGetMe(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	writeJSON(w, r, http.StatusOK, newUserResponse(user))
+}
+
+// handleChangePassword changes the password of the user to "new_password" after checking the
+// "current_password", every other session of the user is logged out and their access tokens
+// are revoked.
+func (s *gymlogServer) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	session, _ := sessionFromContext(r.Context())
+	if !s.reauthenticate(w, r, user, r.FormValue("current_password")) {
+		return
+	}
+	password := r.FormValue("new_password")
+	if err := domain.ValidatePassword(password); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := s.userRepository.ChangePassword(user.ID, session.ID, passwordHash); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleChangeEmail changes the email of the user to "email" after checking their "password",
+// and sends a verification token to the new email.
+func (s *gymlogServer) handleChangeEmail(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	if !s.reauthenticate(w, r, user, r.FormValue("password")) {
+		return
+	}
+	email := r.FormValue("email")
+	if err := domain.ValidateEmail(email); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err := s.userRepository.ChangeEmail(user.ID, email)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// The email is changed even if the token cannot be sent, the user can ask for it again later
+	if err := s.userRepository.SendEmailVerification(r.Context(), user.ID); err != nil {
+		log.Printf("[%s] sending the email verification of %s: %v", requestIDFromContext(r.Context()), user.Username, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeleteAccount schedules the deletion of the account after checking the "password", the
+// user can cancel it with handleCancelAccountDeletion until the grace period is over.
+func (s *gymlogServer) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	if !s.reauthenticate(w, r, user, r.FormValue("password")) {
+		return
+	}
+
+	deleteAt, err := s.userRepository.ScheduleAccountDeletion(user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusAccepted, accountDeletionResponse{DeleteAt: deleteAt})
+}
+
+// handleCancelAccountDeletion keeps the account of a user that asked to delete it.
+func (s *gymlogServer) handleCancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	err := s.userRepository.CancelAccountDeletion(user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/json"
+	"gymlog/domain"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestChangePassword(t *testing.T) {
+	h := newTestHandler(t)
+	phone := registerAndLogin(t, h, "alice")
+	laptop := login(t, h, "alice")
+	token := phone.createAccessToken(t, h, `{"name":"cron","scope":"read"}`)
+	bob := registerAndLogin(t, h, "bob")
+	bobToken := bob.createAccessToken(t, h, `{"name":"cron","scope":"read"}`)
+
+	change := func(current, password string) int {
+		query := url.Values{"current_password": {current}, "new_password": {password}}
+		return phone.do(h, http.MethodPost, "/api/v1/account/password?"+query.Encode(), "").Code
+	}
+	if got := change("nope", "a brand new password"); got != http.StatusForbidden {
+		t.Fatalf("wrong current password: got %d", got)
+	}
+	if got := change(testPassword, "short"); got != http.StatusBadRequest {
+		t.Fatalf("short new password: got %d", got)
+	}
+	if got := change(testPassword, "a brand new password"); got != http.StatusNoContent {
+		t.Fatalf("change password: got %d", got)
+	}
+
+	if rec := phone.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusOK {
+		t.Fatalf("session that changed the password: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := laptop.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("other session after the change: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := doWithToken(h, token.Token, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("access token after the change: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := doWithToken(h, bobToken.Token, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusOK {
+		t.Fatalf("access token of another user after the change: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {testPassword}}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("login with old password: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {"a brand new password"}}); rec.Code != http.StatusOK {
+		t.Fatalf("login with new password: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	h, mails := newTestHandlerWithMailer(t)
+	alice := registerAndLogin(t, h, "alice")
+	registerAndLogin(t, h, "bob")
+	oldToken := tokenFromEmail(t, mails.Messages()[0].Body)
+	sent := len(mails.Messages())
+
+	change := func(email, password string) int {
+		query := url.Values{"email": {email}, "password": {password}}
+		return alice.do(h, http.MethodPost, "/api/v1/account/email?"+query.Encode(), "").Code
+	}
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     int
+	}{
+		{"wrong password", "alice@example.com", "nope", http.StatusForbidden},
+		{"invalid email", "alice", testPassword, http.StatusBadRequest},
+		{"same email", "Alice@gymlog.test", testPassword, http.StatusBadRequest},
+		{"email of another user", "BOB@gymlog.test", testPassword, http.StatusConflict},
+		{"new email", "alice@example.com", testPassword, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := change(tt.email, tt.password); got != tt.want {
+				t.Fatalf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	messages := mails.Messages()[sent:]
+	if len(messages) != 1 || messages[0].To != "alice@example.com" {
+		t.Fatalf("got emails %+v", messages)
+	}
+	if rec := serveForm(h, "/api/v1/email/verify", url.Values{"token": {oldToken}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("verify the old email: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/email/verify", url.Values{"token": {tokenFromEmail(t, messages[0].Body)}}); rec.Code != http.StatusOK {
+		t.Fatalf("verify the new email: got %d %s", rec.Code, rec.Body)
+	}
+	sent = len(mails.Messages())
+	if rec := serveForm(h, "/api/v1/password/reset", url.Values{"email": {"alice@example.com"}}); rec.Code != http.StatusOK {
+		t.Fatalf("reset password with the new email: got %d %s", rec.Code, rec.Body)
+	}
+	if messages := waitForEmails(t, mails, sent+1); messages[len(messages)-1].To != "alice@example.com" {
+		t.Fatalf("password reset sent to %s", messages[len(messages)-1].To)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	if rec := alice.do(h, http.MethodPost, "/api/v1/account/delete?password=nope", ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("delete with wrong password: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/account/delete/cancel", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("cancel without a deletion: got %d %s", rec.Code, rec.Body)
+	}
+
+	rec := alice.do(h, http.MethodPost, "/api/v1/account/delete?password="+testPassword, "")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("delete: got %d %s", rec.Code, rec.Body)
+	}
+	var response accountDeletionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if wait := time.Until(response.DeleteAt); wait < domain.AccountDeletionGracePeriod-time.Minute || wait > domain.AccountDeletionGracePeriod {
+		t.Fatalf("deletion scheduled at %v", response.DeleteAt)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/account/delete?password="+testPassword, ""); rec.Code != http.StatusConflict {
+		t.Fatalf("delete twice: got %d %s", rec.Code, rec.Body)
+	}
+
+	// The account keeps working during the grace period, so the user can change their mind.
+	alice = login(t, h, "alice")
+	if rec := alice.do(h, http.MethodPost, "/api/v1/account/delete/cancel", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("cancel: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/account/delete/cancel", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("cancel twice: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestAccountChangesLockout(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		field string
+		form  url.Values
+	}{
+		{"change password", "/api/v1/account/password", "current_password", url.Values{"new_password": {"a brand new password"}}},
+		{"change email", "/api/v1/account/email", "password", url.Values{"email": {"alice@example.test"}}},
+		{"delete account", "/api/v1/account/delete", "password", url.Values{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			alice := registerAndLogin(t, h, "alice")
+
+			send := func(password string) int {
+				tt.form.Set(tt.field, password)
+				return alice.do(h, http.MethodPost, tt.path+"?"+tt.form.Encode(), "").Code
+			}
+			for range 5 {
+				if got := send("wrong"); got != http.StatusForbidden {
+					t.Fatalf("wrong password: got %d", got)
+				}
+			}
+			if got := send(testPassword); got != http.StatusTooManyRequests {
+				t.Fatalf("right password during the lockout: got %d", got)
+			}
+		})
+	}
+}
@@ -4,139 +4,267 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"gymlog/adapters/application"
 	"gymlog/domain"
+	"log"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 func (s *gymlogServer) handleRegister(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Must be a POST request", http.StatusMethodNotAllowed)
-		return
-	}
-
 	username := r.FormValue("username")
 	email := r.FormValue("email")
 	password := r.FormValue("password")
 
-	users, err := s.userRepository.Users(username)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// Every invalid field is reported at once, so the client can show them all on the form
+	user, err := domain.CreateUser(domain.User{Username: username, Email: email})
+	if err := errors.Join(err, domain.ValidatePassword(password)); err != nil {
+		writeError(w, r, err)
 		return
 	}
-	if len(users) > 0 {
-		http.Error(w, "Username already exists", http.StatusBadRequest)
+
+	user.PasswordHash, err = hashPassword(password)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	hashedPassword, err := hashPassword(password)
+	// The storage enforces unique usernames and emails, a check before saving would race
+	user.ID, err = s.userRepository.SaveUser(user)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	user := domain.User{
-		Username:     username,
-		Email:        email,
-		PasswordHash: hashedPassword,
+	// The account exists even if the email cannot be sent, the user can ask for it again later
+	err = s.userRepository.SendEmailVerification(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("[%s] sending the email verification of %s: %v", requestIDFromContext(r.Context()), username, err)
 	}
 
-	if err := s.userRepository.SaveUser(user); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+	// The account is only reachable at /me once logged in, so there is no Location to send
+	writeJSON(w, r, http.StatusCreated, newUserResponse(user))
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("User registered successfully"))
+// userResponse is the account of a user as shown to them, without the password hash.
+type userResponse struct {
+	ID                  int        `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
 }
 
-func (s *gymlogServer) handleLogin(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Must be a POST request", http.StatusMethodNotAllowed)
-		return
+func newUserResponse(user domain.User) userResponse {
+	return userResponse{
+		ID:                  user.ID,
+		Username:            user.Username,
+		Email:               user.Email,
+		EmailVerifiedAt:     user.EmailVerifiedAt,
+		DeletionScheduledAt: user.DeletionScheduledAt,
 	}
+}
 
+func (s *gymlogServer) handleLogin(w http.ResponseWriter, r *http.Request) {
 	username := r.FormValue("username")
 	password := r.FormValue("password")
+	ip := clientIP(r)
+
+	if s.loginLocked(w, r, username, ip) {
+		return
+	}
 
 	user, err := s.userRepository.Users(username)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	// Unknown users get the same answer, after the same bcrypt work, as a wrong password, so
+	// logins cannot tell which usernames exist
+	passwordHash := dummyPasswordHash()
+	if len(user) > 0 {
+		passwordHash = user[0].PasswordHash
+	}
+	if !checkPasswordHash(password, passwordHash) || len(user) == 0 {
+		if err := s.userRepository.RecordLoginAttempt(username, ip, domain.LoginFailed); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeError(w, r, errIncorrectLogin)
+		return
+	}
+
+	// With two-factor authentication the session only starts after POST /login/mfa, which
+	// records the attempt
+	mfaEnabled, err := s.userRepository.TOTPEnabled(user[0].ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if mfaEnabled {
+		mfaToken, err := s.userRepository.StartMFAChallenge(user[0].ID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusAccepted, mfaRequiredResponse{MFARequired: true, MFAToken: mfaToken})
 		return
 	}
 
-	if len(user) == 0 {
-		http.Error(w, "User not found", http.StatusNotFound)
+	if err := s.userRepository.RecordLoginAttempt(username, ip, domain.LoginSucceeded); err != nil {
+		writeError(w, r, err)
 		return
 	}
+	s.startSession(w, r, user[0].ID)
+}
 
-	if !checkPasswordHash(password, user[0].PasswordHash) {
-		http.Error(w, "Incorrect password, never try again", http.StatusUnauthorized)
+// loginLocked answers with 429 and records the attempt when the username or the IP has to wait
+// before trying a password again, reporting whether it did.
+func (s *gymlogServer) loginLocked(w http.ResponseWriter, r *http.Request, username, ip string) bool {
+	delay, err := s.userRepository.LoginDelay(username, ip)
+	if err != nil {
+		writeError(w, r, err)
+		return true
+	}
+	if delay == 0 {
+		return false
+	}
+	if err := s.userRepository.RecordLoginAttempt(username, ip, domain.LoginLocked); err != nil {
+		writeError(w, r, err)
+		return true
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+	writeError(w, r, errTooManyLogins)
+	return true
+}
+
+// reauthenticate checks the password the logged in user types again before a sensitive change,
+// with the same lockout and audit log as logins so a stolen session cannot guess it. It answers
+// the request and returns false when the password is not accepted.
+func (s *gymlogServer) reauthenticate(w http.ResponseWriter, r *http.Request, user domain.User, password string) bool {
+	ip := clientIP(r)
+	if s.loginLocked(w, r, user.Username, ip) {
+		return false
+	}
+	outcome := domain.LoginSucceeded
+	if !checkPasswordHash(password, user.PasswordHash) {
+		outcome = domain.LoginFailed
+	}
+	if err := s.userRepository.RecordLoginAttempt(user.Username, ip, outcome); err != nil {
+		writeError(w, r, err)
+		return false
+	}
+	if outcome == domain.LoginFailed {
+		writeError(w, r, errIncorrectPassword)
+		return false
+	}
+	return true
+}
+
+// handleLoginMFA completes the login of a user with two-factor authentication, with the
+// "mfa_token" of the first step and a "code" of their authenticator app or a recovery code.
+func (s *gymlogServer) handleLoginMFA(w http.ResponseWriter, r *http.Request) {
+	mfaToken := r.FormValue("mfa_token")
+	code := r.FormValue("code")
+	if mfaToken == "" || code == "" {
+		writeError(w, r, errors.Join(requiredField("mfa_token", mfaToken), requiredField("code", code)))
+		return
+	}
+
+	userID, mfaErr := s.userRepository.CompleteMFAChallenge(mfaToken, code)
+	if errors.Is(mfaErr, application.ErrInvalidMFAChallenge) {
+		writeError(w, r, mfaErr)
+		return
+	}
+	outcome := domain.LoginSucceeded
+	if errors.Is(mfaErr, application.ErrInvalidMFACode) {
+		outcome = domain.LoginFailed
+	} else if mfaErr != nil {
+		writeError(w, r, mfaErr)
+		return
+	}
+
+	// Wrong codes count as failed logins of the user, like wrong passwords
+	user, err := s.userRepository.User(userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := s.userRepository.RecordLoginAttempt(user.Username, clientIP(r), outcome); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if mfaErr != nil {
+		// A wrong code is a failed login, not a malformed request like when confirming the setup
+		writeProblem(w, r, http.StatusUnauthorized, application.ErrInvalidMFACode.Code, mfaErr.Error())
 		return
 	}
 
+	s.startSession(w, r, userID)
+}
+
+type mfaRequiredResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+// startSession logs in the user on the device of the request, setting the session cookies.
+func (s *gymlogServer) startSession(w http.ResponseWriter, r *http.Request, userID int) {
 	sessionToken, err := generateToken(32)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 	csrfToken, err := generateToken(32)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	// Store token in database, the sessions of the other devices stay active
+	session, err := s.userRepository.SaveSession(domain.UserSession{
+		UserID:       userID,
+		SessionToken: sessionToken,
+		CSRFToken:    csrfToken,
+		DeviceName:   deviceName(r),
+		UserAgent:    r.UserAgent(),
+		IP:           clientIP(r),
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// The server renews the session while it is used, so the cookies live as long as the session can
+	cookieExpires := session.CreatedAt.Add(s.userRepository.SessionPolicy().MaxLifetime)
 	http.SetCookie(w, &http.Cookie{
 		Name:     "session_token",
 		Value:    sessionToken,
-		Expires:  time.Now().Add(24 * time.Hour),
+		Expires:  cookieExpires,
 		HttpOnly: true,
 	})
 
 	http.SetCookie(w, &http.Cookie{
 		Name:     "csrf_token",
 		Value:    csrfToken,
-		Expires:  time.Now().Add(24 * time.Hour),
+		Expires:  cookieExpires,
 		HttpOnly: false,
 	})
 
-	// Store token in database
-	err = s.userRepository.SaveSession(user[0].ID, sessionToken, csrfToken)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Login successful"))
+	writeMessage(w, r, "Login successful")
 }
 
-// handleLogout logs out a user by deleting the session from the database.
+// handleLogout logs out the current session, the other devices of the user stay logged in.
 func (s *gymlogServer) handleLogout(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Must be a POST request", http.StatusMethodNotAllowed)
-		return
-	}
-
-	if err := s.Authorize(r); err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
-		return
-	}
-
-	username := r.FormValue("username")
-	user, err := s.userRepository.UserSession(username)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	if user.SessionToken == "" || user.CSRFToken == "" {
-		http.Error(w, "User not logged in", http.StatusUnauthorized)
-		return
-	}
+	session, _ := sessionFromContext(r.Context())
 
 	http.SetCookie(w, &http.Cookie{
 		Name:     "session_token",
@@ -151,18 +279,45 @@ func (s *gymlogServer) handleLogout(w http.ResponseWriter, r *http.Request) {
 		HttpOnly: false,
 	})
 
-	err = s.userRepository.DeleteSession(user.UserID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	err := s.userRepository.DeleteSession(session.UserID, session.ID)
+	if err != nil && !errors.Is(err, application.ErrSessionNotFound) {
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Logout successful"))
+	writeMessage(w, r, "Logout successful")
+}
+
+// deviceName is the name the user gave to the device logging in, or its user agent. The user
+// repository cuts the long ones.
+func deviceName(r *http.Request) string {
+	name := strings.TrimSpace(r.FormValue("device_name"))
+	if name == "" {
+		name = r.UserAgent()
+	}
+	return name
 }
 
-// handleGetSession returns the session token and CSRF token for a user.
+// clientIP returns the IP address of the client connection.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// dummyPasswordHash is compared with the passwords of unknown users, so that logging in to them
+// takes as long as logging in to a real account.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := hashPassword("not the password of any user")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
+// hashPassword hashes a password with bcrypt.
 func hashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 10)
 	if err != nil {
@@ -187,26 +342,3 @@ func generateToken(length int) (string, error) {
 	}
 	return base64.RawURLEncoding.EncodeToString(bytes), nil
 }
-
-func (s *gymlogServer) Authorize(r *http.Request) error {
-	username := r.FormValue("username")
-	user, err := s.userRepository.UserSession(username)
-	if err != nil {
-		return err
-	}
-	if user.SessionToken == "" || user.CSRFToken == "" {
-		return errors.New("Unauthorized")
-	}
-
-	st, err := r.Cookie("session_token")
-	if err != nil || st.Value == "" || st.Value != user.SessionToken {
-		return errors.New("Unauthorized")
-	}
-
-	csrf := r.Header.Get("X-CSRF-Token")
-	if csrf == "" || csrf != user.CSRFToken {
-		return errors.New("Unauthorized")
-	}
-
-	return nil
-}
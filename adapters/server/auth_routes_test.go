@@ -0,0 +1,134 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	tests := []struct {
+		name string
+		path string
+		form url.Values
+		want int
+	}{
+		{"repeated username", "/api/v1/register", url.Values{"username": {"alice"}, "email": {"other@gymlog.test"}, "password": {testPassword}}, http.StatusConflict},
+		{"repeated username in another case", "/api/v1/register", url.Values{"username": {"ALICE"}, "email": {"other@gymlog.test"}, "password": {testPassword}}, http.StatusConflict},
+		{"repeated email in another case", "/api/v1/register", url.Values{"username": {"bob"}, "email": {"Alice@GymLog.test"}, "password": {testPassword}}, http.StatusConflict},
+		{"short username", "/api/v1/register", url.Values{"username": {"bo"}, "email": {"bob@gymlog.test"}, "password": {testPassword}}, http.StatusBadRequest},
+		{"username with spaces", "/api/v1/register", url.Values{"username": {"bob smith"}, "email": {"bob@gymlog.test"}, "password": {testPassword}}, http.StatusBadRequest},
+		{"short password", "/api/v1/register", url.Values{"username": {"bob"}, "email": {"bob@gymlog.test"}, "password": {"secret"}}, http.StatusBadRequest},
+		{"blank password", "/api/v1/register", url.Values{"username": {"bob"}, "email": {"bob@gymlog.test"}, "password": {"          "}}, http.StatusBadRequest},
+		{"login in another case", "/api/v1/login", url.Values{"username": {"Alice"}, "password": {testPassword}}, http.StatusOK},
+		{"unknown user", "/api/v1/login", url.Values{"username": {"bob"}, "password": {testPassword}}, http.StatusUnauthorized},
+		{"wrong password", "/api/v1/login", url.Values{"username": {"alice"}, "password": {"nope"}}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serveForm(h, tt.path, tt.form); rec.Code != tt.want {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+
+	if rec := alice.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusOK {
+		t.Fatalf("failed logins changed the session: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestLoginKeepsOtherSessions(t *testing.T) {
+	h := newTestHandler(t)
+	phone := registerAndLogin(t, h, "alice")
+	laptop := login(t, h, "alice")
+
+	if rec := phone.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusOK {
+		t.Fatalf("phone session: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := laptop.do(h, http.MethodPost, "/api/v1/logout", ""); rec.Code != http.StatusOK {
+		t.Fatalf("laptop logout: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := laptop.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("laptop after logout: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := phone.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusOK {
+		t.Fatalf("phone after laptop logout: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	withoutCSRF := alice
+	withoutCSRF.csrf = ""
+	if rec := withoutCSRF.do(h, http.MethodPost, "/api/v1/logout", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("logout without CSRF token: got %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := alice.do(h, http.MethodPost, "/api/v1/logout", ""); rec.Code != http.StatusOK {
+		t.Fatalf("logout: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("request after logout: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"session token", "Bearer " + alice.sessionToken, http.StatusOK},
+		{"lowercase scheme", "bearer " + alice.sessionToken, http.StatusOK},
+		{"unknown token", "Bearer nope", http.StatusUnauthorized},
+		{"other scheme", "Basic " + alice.sessionToken, http.StatusUnauthorized},
+		{"no header", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Bearer requests do not need the CSRF token, browsers never add the header on their own.
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/routines", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	h := newTestHandler(t)
+	registerAndLogin(t, h, "alice")
+
+	unknown := serveForm(h, "/api/v1/login", url.Values{"username": {"bob"}, "password": {testPassword}})
+	wrong := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {"nope"}})
+	if unknown.Code != wrong.Code || decodeProblem(t, unknown).Code != decodeProblem(t, wrong).Code {
+		t.Fatalf("unknown user got %d %q, wrong password got %d %q", unknown.Code, unknown.Body, wrong.Code, wrong.Body)
+	}
+
+	for range 4 {
+		if rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {"nope"}}); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("wrong password: got %d %s", rec.Code, rec.Body)
+		}
+	}
+	rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {testPassword}})
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("right password during the lockout: got %d %s, headers %v", rec.Code, rec.Body, rec.Header())
+	}
+	if rec := serveForm(h, "/api/v1/login", url.Values{"username": {"bob"}, "password": {testPassword}}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("other username: got %d %s, want no lockout", rec.Code, rec.Body)
+	}
+}
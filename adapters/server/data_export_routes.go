@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"gymlog/domain"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// dataExportResponse is a data export as listed to its user, DownloadURL is set once it is ready.
+type dataExportResponse struct {
+	ID          int                     `json:"id"`
+	Status      domain.DataExportStatus `json:"status"`
+	CreatedAt   time.Time               `json:"created_at"`
+	CompletedAt *time.Time              `json:"completed_at"`
+	ExpiresAt   *time.Time              `json:"expires_at"`
+	DownloadURL string                  `json:"download_url,omitempty"`
+}
+
+func newDataExportResponse(export domain.DataExport) dataExportResponse {
+	response := dataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == domain.DataExportReady {
+		response.DownloadURL = fmt.Sprintf(apiV1+"/exports/%d/download", export.ID)
+	}
+	return response
+}
+
+// handleRequestDataExport starts exporting all the data of the user, the archive is built in the
+// background and listed by handleGetDataExport when it is ready.
+func (s *gymlogServer) handleRequestDataExport(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	export, err := s.userRepository.RequestDataExport(user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// The Location is where to follow the progress of the export
+	w.Header().Set("Location", fmt.Sprintf(apiV1+"/exports/%d", export.ID))
+	writeJSON(w, r, http.StatusAccepted, newDataExportResponse(export))
+}
+
+// handleGetDataExports lists the data exports of the user that can still be downloaded.
+func (s *gymlogServer) handleGetDataExports(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	exports, err := s.userRepository.DataExports(user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response := make([]dataExportResponse, 0, len(exports))
+	for _, export := range exports {
+		response = append(response, newDataExportResponse(export))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// handleGetDataExport returns the status of a data export of the user.
+func (s *gymlogServer) handleGetDataExport(w http.ResponseWriter, r *http.Request) {
+	exportID, err := idFromPath(r, "export")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	export, err := s.userRepository.DataExport(user.ID, exportID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newDataExportResponse(export))
+}
+
+// handleDownloadDataExport sends the ZIP archive of a ready data export, until it expires.
+func (s *gymlogServer) handleDownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	exportID, err := idFromPath(r, "export")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	archive, err := s.userRepository.DataExportArchive(user.ID, exportID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="gymlog-export-%d.zip"`, exportID))
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(archive)
+}
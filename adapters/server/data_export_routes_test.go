@@ -0,0 +1,135 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gymlog/adapters/application"
+	"gymlog/adapters/mailer"
+	"gymlog/adapters/storage"
+	"gymlog/domain"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDataExport(t *testing.T) {
+	store, err := storage.NewMemoryStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The exports are built by the user repository in the background, the test builds them itself.
+	users := application.NewUserRepo(store, domain.DefaultSessionPolicy, mailer.NewLogMailer(io.Discard))
+	h := NewServer(application.NewGymRepository(store), users, Config{}).loadHandlers()
+
+	alice := registerAndLogin(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+	routineID := alice.createRoutine(t, h, "push day")
+	rec := alice.do(h, http.MethodPost, "/api/v1/workouts", fmt.Sprintf(`{"routine_id":%d}`, routineID))
+	workout := decodeWorkout(t, rec.Body.Bytes())
+	if rec := alice.do(h, http.MethodPost, fmt.Sprintf("/api/v1/workouts/%d/sets", workout.ID), `{"exercise_id":1,"set_index":1,"reps":8,"weight":62.5}`); rec.Code != http.StatusOK {
+		t.Fatalf("add set: got %d %s", rec.Code, rec.Body)
+	}
+
+	rec = alice.do(h, http.MethodPost, "/api/v1/exports", "")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("request export: got %d %s", rec.Code, rec.Body)
+	}
+	var export dataExportResponse
+	if err := json.NewDecoder(rec.Body).Decode(&export); err != nil {
+		t.Fatal(err)
+	}
+	if export.Status != domain.DataExportPending || export.DownloadURL != "" {
+		t.Fatalf("got %+v", export)
+	}
+	exportPath := fmt.Sprintf("/api/v1/exports/%d", export.ID)
+
+	if rec := alice.do(h, http.MethodPost, "/api/v1/exports", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("request a second export while pending: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodGet, exportPath+"/download", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("download before it is ready: got %d %s", rec.Code, rec.Body)
+	}
+
+	if built, err := users.BuildDataExports(); err != nil || built != 1 {
+		t.Fatalf("built %d exports, %v", built, err)
+	}
+	rec = alice.do(h, http.MethodGet, exportPath, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get export: got %d %s", rec.Code, rec.Body)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&export); err != nil {
+		t.Fatal(err)
+	}
+	if export.Status != domain.DataExportReady || export.DownloadURL != exportPath+"/download" || export.ExpiresAt == nil {
+		t.Fatalf("got %+v", export)
+	}
+	if rec := bob.do(h, http.MethodGet, exportPath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("bob gets alice's export: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := bob.do(h, http.MethodGet, exportPath+"/download", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("bob downloads alice's export: got %d %s", rec.Code, rec.Body)
+	}
+
+	rec = alice.do(h, http.MethodGet, export.DownloadURL, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("download: got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name] = string(content)
+	}
+
+	contains := map[string][]string{
+		"user.json":         {`"username": "alice"`, `"email": "alice@gymlog.test"`},
+		"sessions.csv":      {"id,device_name,user_agent,ip"},
+		"access_tokens.csv": {"id,name,scope"},
+		"routines.json":     {`"Name": "push day"`},
+		"exercises.json":    {"[]"},
+		"workouts.json":     {fmt.Sprintf(`"RoutineID": %d`, routineID)},
+		"workout_sets.csv":  {fmt.Sprintf("%d,%d,", workout.ID, routineID), ",1,1,8,62.5,false"},
+	}
+	for name, wants := range contains {
+		content, exists := files[name]
+		if !exists {
+			t.Fatalf("the export has no %s, got files %v", name, archive.File)
+		}
+		for _, want := range wants {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s does not contain %q:\n%s", name, want, content)
+			}
+		}
+	}
+	for name, content := range files {
+		if strings.Contains(content, "$2a$") || strings.Contains(content, "bob") {
+			t.Errorf("%s has a password hash or data of another user:\n%s", name, content)
+		}
+	}
+
+	rec = alice.do(h, http.MethodGet, "/api/v1/exports", "")
+	var exports []dataExportResponse
+	if err := json.NewDecoder(rec.Body).Decode(&exports); err != nil {
+		t.Fatal(err)
+	}
+	if len(exports) != 1 || exports[0].ID != export.ID {
+		t.Fatalf("got exports %+v", exports)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/exports", ""); rec.Code != http.StatusAccepted {
+		t.Fatalf("request another export once ready: got %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+)
+
+// handleVerifyEmail verifies the email of the user of the "token" of the form.
+func (s *gymlogServer) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.FormValue("token")
+	if token == "" {
+		writeError(w, r, requiredField("token", token))
+		return
+	}
+
+	err := s.userRepository.VerifyEmail(token)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMessage(w, r, "Email verified")
+}
+
+// handleResendEmailVerification sends a new verification email to the user, a few times a day.
+func (s *gymlogServer) handleResendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+
+	err := s.userRepository.SendEmailVerification(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMessage(w, r, "Verification email sent")
+}
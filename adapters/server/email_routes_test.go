@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRegisterValidatesEmail(t *testing.T) {
+	h := newTestHandler(t)
+
+	for _, email := range []string{"", "alice", "alice@", "Alice <alice@gymlog.test>", " alice@gymlog.test"} {
+		form := url.Values{"username": {"alice"}, "email": {email}, "password": {testPassword}}
+		if rec := serveForm(h, "/api/v1/register", form); rec.Code != http.StatusBadRequest {
+			t.Fatalf("register with email %q: got %d %s", email, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+	h, mails := newTestHandlerWithConfig(t, Config{RequireVerifiedEmail: true})
+	alice := registerAndLogin(t, h, "alice")
+	registerAndLogin(t, h, "bob")
+	routinePath := fmt.Sprintf("/api/v1/routines/%d", alice.createRoutine(t, h, "push day"))
+
+	messages := mails.Messages()
+	if len(messages) != 2 || messages[0].To != "alice@gymlog.test" {
+		t.Fatalf("got emails %+v, want one per registration", messages)
+	}
+
+	if rec := alice.do(h, http.MethodPost, routinePath+"/share", `{"share_with":"bob"}`); rec.Code != http.StatusForbidden {
+		t.Fatalf("share before verifying: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/email/verify/resend", ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("resend right after registering: got %d %s", rec.Code, rec.Body)
+	}
+
+	token := tokenFromEmail(t, messages[0].Body)
+	if rec := serveForm(h, "/api/v1/email/verify", url.Values{"token": {"nope"}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("verify with unknown token: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/email/verify", url.Values{"token": {token}}); rec.Code != http.StatusOK {
+		t.Fatalf("verify: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/email/verify", url.Values{"token": {token}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("token used twice: got %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := alice.do(h, http.MethodPost, routinePath+"/share", `{"share_with":"bob"}`); rec.Code != http.StatusNoContent {
+		t.Fatalf("share after verifying: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/email/verify/resend", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("resend after verifying: got %d %s", rec.Code, rec.Body)
+	}
+}
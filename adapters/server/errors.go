@@ -0,0 +1,147 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gymlog/adapters/application"
+	"gymlog/domain"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// The errors of the server itself, the application errors come from the repositories.
+var (
+	// errUnauthorized is returned when a request has no valid session or access token.
+	errUnauthorized      = serverError(application.KindUnauthorized, "unauthorized", "log in or send a valid access token")
+	errInvalidCSRF       = serverError(application.KindUnauthorized, "invalid_csrf_token", "the X-CSRF-Token header is missing or does not match the session")
+	errIncorrectLogin    = serverError(application.KindUnauthorized, "incorrect_login", "incorrect username or password")
+	errIncorrectPassword = serverError(application.KindForbidden, "incorrect_password", "incorrect password")
+	errReadOnlyToken     = serverError(application.KindForbidden, "read_only_token", "access token does not have the write scope")
+	errSessionRequired   = serverError(application.KindForbidden, "session_required", "access tokens cannot manage sessions or tokens, log in instead")
+	errEmailNotVerified  = serverError(application.KindForbidden, "email_not_verified", "verify your email first")
+	errTooManyLogins     = serverError(application.KindTooManyRequests, "too_many_logins", "too many failed logins, try again later")
+	errInvalidBody       = serverError(application.KindInvalid, "invalid_body", "request body is not valid JSON")
+)
+
+func serverError(kind application.ErrorKind, code, message string) *application.Error {
+	return &application.Error{Kind: kind, Code: code, Message: message}
+}
+
+// problem is the body of every error response, in the problem details format of RFC 7807.
+// Code is stable for clients to check, Detail is meant for people and can change.
+type problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []fieldError `json:"errors,omitempty"`
+}
+
+// fieldError is an invalid field of the request, named like the form value or JSON field.
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// writeProblem answers with a problem of the given status.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string, fieldErrors ...fieldError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Code:      code,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: requestIDFromContext(r.Context()),
+		Errors:    fieldErrors,
+	})
+	if err != nil {
+		log.Printf("[%s] encode problem: %v", requestIDFromContext(r.Context()), err)
+	}
+}
+
+// writeError answers with the problem that matches the error. Application errors and invalid
+// fields are explained to the client, any other error is logged and reported as an internal
+// error without details, as it can reveal how the server works.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	fields := fieldErrors(err)
+	var appErr *application.Error
+	switch {
+	case errors.As(err, &appErr):
+		writeProblem(w, r, statusOf(appErr.Kind), appErr.Code, detailOf(err), fields...)
+	case len(fields) > 0:
+		writeProblem(w, r, http.StatusBadRequest, "validation_failed", detailOf(err), fields...)
+	default:
+		log.Printf("[%s] %s %s: %v", requestIDFromContext(r.Context()), r.Method, r.URL.Path, err)
+		writeProblem(w, r, http.StatusInternalServerError, "internal_error", "internal server error")
+	}
+}
+
+func statusOf(kind application.ErrorKind) int {
+	switch kind {
+	case application.KindInvalid:
+		return http.StatusBadRequest
+	case application.KindUnauthorized:
+		return http.StatusUnauthorized
+	case application.KindForbidden:
+		return http.StatusForbidden
+	case application.KindNotFound:
+		return http.StatusNotFound
+	case application.KindConflict:
+		return http.StatusConflict
+	case application.KindGone:
+		return http.StatusGone
+	case application.KindTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// detailOf joins the lines of errors.Join into one sentence.
+func detailOf(err error) string {
+	return strings.ReplaceAll(err.Error(), "\n", "; ")
+}
+
+// fieldErrors collects the invalid fields of the error, also inside wrapped and joined errors.
+func fieldErrors(err error) []fieldError {
+	var validation *domain.ValidationError
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case interface{ Unwrap() []error }:
+		var fields []fieldError
+		for _, err := range e.Unwrap() {
+			fields = append(fields, fieldErrors(err)...)
+		}
+		return fields
+	case interface{ Unwrap() error }:
+		return fieldErrors(e.Unwrap())
+	default:
+		if errors.As(err, &validation) {
+			return []fieldError{{Field: validation.Field, Message: validation.Message}}
+		}
+		return nil
+	}
+}
+
+// requiredField returns a ValidationError when the value of a required field is empty.
+func requiredField(field, value string) error {
+	if value == "" {
+		return invalidField(field, field+" is required")
+	}
+	return nil
+}
+
+// invalidField is a ValidationError for request values that the domain does not validate,
+// like a missing token or a malformed number.
+func invalidField(field, message string) error {
+	return &domain.ValidationError{Field: field, Message: message}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) problem {
+	t.Helper()
+	if got := rec.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Fatalf("got Content-Type %q for %d %s", got, rec.Code, rec.Body)
+	}
+	var p problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Status != rec.Code || p.Title != http.StatusText(rec.Code) || p.RequestID != rec.Header().Get("X-Request-ID") {
+		t.Fatalf("got problem %+v for status %d and request %s", p, rec.Code, rec.Header().Get("X-Request-ID"))
+	}
+	return p
+}
+
+func TestErrorsAreProblems(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	tests := []struct {
+		name   string
+		rec    *httptest.ResponseRecorder
+		status int
+		code   string
+		fields []string
+	}{
+		{"invalid fields", serveForm(h, "/api/v1/register", url.Values{"username": {"b"}, "email": {"bob"}, "password": {"short"}}),
+			http.StatusBadRequest, "validation_failed", []string{"username", "password"}},
+		{"taken username", serveForm(h, "/api/v1/register", url.Values{"username": {"ALICE"}, "email": {"other@gymlog.test"}, "password": {testPassword}}),
+			http.StatusConflict, "username_taken", nil},
+		{"invalid routine", alice.do(h, http.MethodPost, "/api/v1/routines", `{"name":"push day","exercises":[{"id":9999,"sets":3,"reps":8}]}`),
+			http.StatusBadRequest, "invalid_routine", []string{"exercises"}},
+		{"invalid body", alice.do(h, http.MethodPost, "/api/v1/routines", `{"name":`),
+			http.StatusBadRequest, "invalid_body", nil},
+		{"invalid ID", alice.do(h, http.MethodGet, "/api/v1/routines/abc", ""),
+			http.StatusBadRequest, "validation_failed", []string{"id"}},
+		{"not found", alice.do(h, http.MethodGet, "/api/v1/routines/9999", ""),
+			http.StatusNotFound, "routine_not_found", nil},
+		{"unauthenticated", testUser{}.do(h, http.MethodGet, "/api/v1/routines", ""),
+			http.StatusUnauthorized, "unauthorized", nil},
+		{"unknown route", alice.do(h, http.MethodGet, "/nope", ""),
+			http.StatusNotFound, "route_not_found", nil},
+		{"wrong method", alice.do(h, http.MethodPatch, "/api/v1/routines", ""),
+			http.StatusMethodNotAllowed, "method_not_allowed", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.rec.Code != tt.status {
+				t.Fatalf("got %d %s, want %d", tt.rec.Code, tt.rec.Body, tt.status)
+			}
+			p := decodeProblem(t, tt.rec)
+			var fields []string
+			for _, field := range p.Errors {
+				fields = append(fields, field.Field)
+			}
+			if p.Code != tt.code || p.Detail == "" || !slices.Equal(fields, tt.fields) {
+				t.Fatalf("got problem %+v, want code %s and fields %v", p, tt.code, tt.fields)
+			}
+		})
+	}
+}
+
+func TestInternalErrorsAreNotLeaked(t *testing.T) {
+	h := chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, errors.New(`pq: relation "users" does not exist`))
+	}), withRequestID)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("got %d %s", rec.Code, rec.Body)
+	}
+	if p := decodeProblem(t, rec); p.Code != "internal_error" || strings.Contains(rec.Body.String(), "users") {
+		t.Fatalf("got problem %s", rec.Body)
+	}
+}
+
+func TestSuccessResponsesAreJSON(t *testing.T) {
+	h := newTestHandler(t)
+	rec := serveForm(h, "/api/v1/register", url.Values{"username": {"alice"}, "email": {"alice@gymlog.test"}, "password": {testPassword}})
+	var response userResponse
+	if rec.Header().Get("Content-Type") != "application/json" || json.Unmarshal(rec.Body.Bytes(), &response) != nil || response.ID == 0 {
+		t.Fatalf("register: got %d %q %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Header().Get("Content-Type") != "application/json" || !json.Valid(rec.Body.Bytes()) {
+		t.Fatalf("health: got %d %q %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+}
+
+func TestResponsesUseSnakeCase(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	if rec := alice.do(h, http.MethodPost, "/api/v1/tokens", `{"name":"cron","scope":"read"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create token: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/exports", ""); rec.Code != http.StatusAccepted {
+		t.Fatalf("request export: got %d %s", rec.Code, rec.Body)
+	}
+	rec := alice.do(h, http.MethodPost, "/api/v1/workouts", "")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("start workout: got %d %s", rec.Code, rec.Body)
+	}
+	workoutPath := rec.Header().Get("Location")
+	if rec := alice.do(h, http.MethodPost, workoutPath+"/sets", `{"exercise_id":1,"set_index":1,"reps":8,"weight":60}`); rec.Code != http.StatusOK {
+		t.Fatalf("add set: got %d %s", rec.Code, rec.Body)
+	}
+
+	for _, path := range []string{"/api/v1/me", "/api/v1/sessions", "/api/v1/tokens", "/api/v1/exports", "/api/v1/workouts", workoutPath} {
+		rec := alice.do(h, http.MethodGet, path, "")
+		var body any
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &body) != nil {
+			t.Fatalf("%s: got %d %s", path, rec.Code, rec.Body)
+		}
+		if list, ok := body.([]any); ok && len(list) > 0 {
+			body = list[0]
+		}
+		item, ok := body.(map[string]any)
+		if !ok {
+			t.Fatalf("%s: got %s", path, rec.Body)
+		}
+		// The sets of a workout are objects too.
+		if sets, ok := item["sets"].([]any); ok && len(sets) > 0 {
+			for key, value := range sets[0].(map[string]any) {
+				item["sets."+key] = value
+			}
+		}
+		for key := range item {
+			if key != strings.ToLower(key) {
+				t.Fatalf("%s: got key %q in %s", path, key, rec.Body)
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gymlog/domain"
+	"net/http"
+	"strconv"
+)
+
+// handleGetExercises handles the GET request for the exercises.
+// Anonymous requests only see the global catalog, authorized ones also get their own exercises.
+// The catalog can be filtered with "target", "muscle", "equipment", "mechanics", "pattern", "unilateral"
+// and "q" (name search), ordered with "sort" and paginated with "limit" (50 by default) and "cursor";
+// the cursor of the next page is returned in the X-Next-Cursor header.
+func (s *gymlogServer) handleGetExercises(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.FormValue("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			writeError(w, r, invalidField("limit", "limit must be a number"))
+			return
+		}
+	}
+	filter := domain.ExerciseFilter{
+		Target:          r.FormValue("target"),
+		Muscle:          r.FormValue("muscle"),
+		Equipment:       r.FormValue("equipment"),
+		Mechanics:       r.FormValue("mechanics"),
+		MovementPattern: r.FormValue("pattern"),
+		Search:          r.FormValue("q"),
+	}
+	if value := r.FormValue("unilateral"); value != "" {
+		unilateral, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, r, invalidField("unilateral", "unilateral must be true or false"))
+			return
+		}
+		filter.Unilateral = &unilateral
+	}
+	query, err := domain.NewExerciseQuery(filter, r.FormValue("sort"), r.FormValue("cursor"), limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	userID := 0
+	if user, ok := userFromContext(r.Context()); ok {
+		userID = user.ID
+	}
+
+	page, err := s.routineRepository.Exercises(userID, query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if page.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+	}
+	writeJSON(w, r, http.StatusOK, page.Exercises)
+}
+
+// handleCreateExercise creates a private exercise for the user.
+func (s *gymlogServer) handleCreateExercise(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+
+	var exerciseRequest postExerciseRequest
+	err := json.NewDecoder(r.Body).Decode(&exerciseRequest)
+	if err != nil {
+		writeError(w, r, errInvalidBody)
+		return
+	}
+
+	exercise, err := domain.CreateExercise(exerciseRequest.toDomain())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	exercise, err = s.routineRepository.CreateExercise(user.ID, exercise)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeCreated(w, r, fmt.Sprintf(apiV1+"/exercises/%d", exercise.ID), exercise)
+}
+
+// handleGetExercise returns an exercise of the global catalog or of the user.
+func (s *gymlogServer) handleGetExercise(w http.ResponseWriter, r *http.Request) {
+	exerciseID, err := idFromPath(r, "exercise")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	exercise, err := s.routineRepository.GetExercise(user.ID, exerciseID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, exercise)
+}
+
+// handleUpdateExercise replaces an exercise created by the user, the global catalog is read only.
+func (s *gymlogServer) handleUpdateExercise(w http.ResponseWriter, r *http.Request) {
+	exerciseID, err := idFromPath(r, "exercise")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var exerciseRequest postExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&exerciseRequest); err != nil {
+		writeError(w, r, errInvalidBody)
+		return
+	}
+	exercise, err := domain.CreateExercise(exerciseRequest.toDomain())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	exercise.ID = exerciseID
+	exercise.OwnerID = &user.ID
+	err = s.routineRepository.UpdateExercise(user.ID, exercise)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, exercise)
+}
+
+// handleDeleteExercise deletes an exercise created by the user that no routine or workout uses.
+func (s *gymlogServer) handleDeleteExercise(w http.ResponseWriter, r *http.Request) {
+	exerciseID, err := idFromPath(r, "exercise")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	err = s.routineRepository.DeleteExercise(user.ID, exerciseID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type postExerciseRequest struct {
+	Name             string   `json:"name"`
+	Target           string   `json:"target"`
+	Equipment        string   `json:"equipment"`
+	Mechanics        string   `json:"mechanics"`
+	MovementPattern  string   `json:"movement_pattern"`
+	Unilateral       bool     `json:"unilateral"`
+	PrimaryMuscles   []string `json:"primary_muscles"`
+	SecondaryMuscles []string `json:"secondary_muscles"`
+	Instructions     string   `json:"instructions"`
+}
+
+func (p postExerciseRequest) toDomain() domain.Exercise {
+	return domain.Exercise{
+		Name:             p.Name,
+		Target:           p.Target,
+		Equipment:        p.Equipment,
+		Mechanics:        p.Mechanics,
+		MovementPattern:  p.MovementPattern,
+		Unilateral:       p.Unilateral,
+		PrimaryMuscles:   p.PrimaryMuscles,
+		SecondaryMuscles: p.SecondaryMuscles,
+		Instructions:     p.Instructions,
+	}
+}
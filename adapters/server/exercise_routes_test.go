@@ -0,0 +1,212 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gymlog/domain"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCustomExercisesArePrivate(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+
+	rec := alice.do(h, http.MethodPost, "/api/v1/exercises", `{"name":"gym 42 hack squat","target":"quads"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create exercise: got %d %s", rec.Code, rec.Body)
+	}
+	var exercise domain.Exercise
+	if err := json.NewDecoder(rec.Body).Decode(&exercise); err != nil {
+		t.Fatal(err)
+	}
+	exercisePath := fmt.Sprintf("/api/v1/exercises/%d", exercise.ID)
+	if location := rec.Header().Get("Location"); location != exercisePath {
+		t.Fatalf("created exercise at %q, want %s", location, exercisePath)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"get", http.MethodGet, exercisePath, "", http.StatusNotFound},
+		{"update", http.MethodPut, exercisePath, `{"name":"mine now","target":"quads"}`, http.StatusNotFound},
+		{"delete", http.MethodDelete, exercisePath, "", http.StatusNotFound},
+		{"use in routine", http.MethodPost, "/api/v1/routines", fmt.Sprintf(`{"name":"legs","exercises":[{"id":%d}]}`, exercise.ID), http.StatusBadRequest},
+		{"update global", http.MethodPut, "/api/v1/exercises/1", `{"name":"mine now","target":"abs"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := bob.do(h, tt.method, tt.path, tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+
+	if rec := alice.do(h, http.MethodGet, exercisePath, ""); rec.Code != http.StatusOK {
+		t.Fatalf("owner get: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func getExercises(t *testing.T, h http.Handler, query string) ([]domain.Exercise, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/exercises?"+query, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get exercises %q: got %d %s", query, rec.Code, rec.Body)
+	}
+	var exercises []domain.Exercise
+	if err := json.NewDecoder(rec.Body).Decode(&exercises); err != nil {
+		t.Fatal(err)
+	}
+	return exercises, rec.Header().Get("X-Next-Cursor")
+}
+
+func TestExerciseCatalogPagination(t *testing.T) {
+	h := newTestHandler(t)
+
+	for _, sort := range []string{"name", "-name", "target", "-id"} {
+		t.Run(sort, func(t *testing.T) {
+			all, cursor := getExercises(t, h, fmt.Sprintf("target=abs&limit=%d&sort=%s", domain.MaxExerciseLimit, sort))
+			if len(all) == 0 || cursor != "" {
+				t.Fatalf("got %d exercises and cursor %q with the max limit", len(all), cursor)
+			}
+
+			var paged []domain.Exercise
+			query := "target=abs&limit=7&sort=" + sort
+			for page := 0; ; page++ {
+				if page > len(all) {
+					t.Fatal("pagination does not end")
+				}
+				exercises, next := getExercises(t, h, query)
+				paged = append(paged, exercises...)
+				if next == "" {
+					break
+				}
+				query = "target=abs&limit=7&sort=" + sort + "&cursor=" + url.QueryEscape(next)
+			}
+
+			if len(paged) != len(all) {
+				t.Fatalf("got %d exercises paginating, want %d", len(paged), len(all))
+			}
+			for i := range all {
+				if paged[i].ID != all[i].ID {
+					t.Fatalf("exercise %d: got ID %d paginating, want %d", i, paged[i].ID, all[i].ID)
+				}
+				if all[i].Target != "abs" {
+					t.Fatalf("exercise %d has target %q", all[i].ID, all[i].Target)
+				}
+			}
+		})
+	}
+}
+
+func TestExerciseCatalogDefaultLimit(t *testing.T) {
+	h := newTestHandler(t)
+
+	exercises, cursor := getExercises(t, h, "")
+	if len(exercises) != domain.DefaultExerciseLimit || cursor == "" {
+		t.Fatalf("got %d exercises and cursor %q without limit", len(exercises), cursor)
+	}
+	next, _ := getExercises(t, h, "cursor="+url.QueryEscape(cursor))
+	if len(next) == 0 || next[0].ID == exercises[0].ID {
+		t.Fatalf("got second page %v", next)
+	}
+}
+
+func TestExerciseCatalogSearch(t *testing.T) {
+	h := newTestHandler(t)
+
+	exercises, _ := getExercises(t, h, "q=dumbbell+curl")
+	if len(exercises) == 0 {
+		t.Fatal("no exercises found")
+	}
+	for _, exercise := range exercises {
+		if !strings.Contains(exercise.Name, "dumbbell") || !strings.Contains(exercise.Name, "curl") {
+			t.Fatalf("exercise %q does not match the search", exercise.Name)
+		}
+	}
+}
+
+func TestExerciseCatalogRejectsInvalidQueries(t *testing.T) {
+	h := newTestHandler(t)
+
+	_, cursor := getExercises(t, h, "limit=5&sort=name")
+	for _, query := range []string{"sort=weight", "limit=-1", "limit=1000", "cursor=nope", "sort=id&cursor=" + url.QueryEscape(cursor)} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/exercises?"+query, nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%q: got %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestExerciseCatalogFilters(t *testing.T) {
+	h := newTestHandler(t)
+
+	exercises, _ := getExercises(t, h, "equipment=barbell&muscle=triceps&mechanics=compound&unilateral=false")
+	if len(exercises) == 0 {
+		t.Fatal("no exercises found")
+	}
+	for _, exercise := range exercises {
+		muscles := append(exercise.PrimaryMuscles, exercise.SecondaryMuscles...)
+		if exercise.Equipment != "barbell" || exercise.Mechanics != "compound" || exercise.Unilateral || !slices.Contains(muscles, "triceps") {
+			t.Fatalf("exercise does not match the filters: %+v", exercise)
+		}
+	}
+
+	exercises, _ = getExercises(t, h, "pattern=lunge&unilateral=true")
+	if len(exercises) == 0 {
+		t.Fatal("no unilateral lunges found")
+	}
+	for _, exercise := range exercises {
+		if exercise.MovementPattern != "lunge" || !exercise.Unilateral {
+			t.Fatalf("exercise does not match the filters: %+v", exercise)
+		}
+	}
+}
+
+func TestCreateExerciseWithMetadata(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	body := `{"name":"landmine press","target":"delts","equipment":"barbell","mechanics":"compound",
+		"movement_pattern":"vertical_push","unilateral":true,"secondary_muscles":["triceps","delts"],"instructions":"Press up and out."}`
+	rec := alice.do(h, http.MethodPost, "/api/v1/exercises", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create exercise: got %d %s", rec.Code, rec.Body)
+	}
+	var created domain.Exercise
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = alice.do(h, http.MethodGet, fmt.Sprintf("/api/v1/exercises/%d", created.ID), "")
+	var exercise domain.Exercise
+	if err := json.NewDecoder(rec.Body).Decode(&exercise); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(exercise.PrimaryMuscles, []string{"delts"}) || !slices.Equal(exercise.SecondaryMuscles, []string{"triceps"}) {
+		t.Fatalf("got muscles %v / %v", exercise.PrimaryMuscles, exercise.SecondaryMuscles)
+	}
+	if exercise.Equipment != "barbell" || exercise.MovementPattern != "vertical_push" || !exercise.Unilateral || exercise.Instructions != "Press up and out." {
+		t.Fatalf("metadata was not saved: %+v", exercise)
+	}
+
+	rec = alice.do(h, http.MethodPost, "/api/v1/exercises", `{"name":"x","target":"delts","equipment":"spaceship"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown equipment: got %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import "net/http"
+
+type enrollTOTPResponse struct {
+	Secret string `json:"secret"`
+	// OTPAuthURI is the otpauth:// URI to show as a QR code for authenticator apps.
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// handleEnrollTOTP starts setting up two-factor authentication with an authenticator app, it is
+// enabled by handleConfirmTOTP with a first code.
+func (s *gymlogServer) handleEnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	secret, uri, err := s.userRepository.EnrollTOTP(user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, enrollTOTPResponse{Secret: secret, OTPAuthURI: uri})
+}
+
+// handleConfirmTOTP enables two-factor authentication with the first "code" of the authenticator
+// app, the response has the recovery codes, which are not shown again.
+func (s *gymlogServer) handleConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	code := r.FormValue("code")
+	if code == "" {
+		writeError(w, r, requiredField("code", code))
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	codes, err := s.userRepository.ConfirmTOTP(user.ID, code)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// handleDisableTOTP turns off two-factor authentication, the user has to type their "password"
+// again so a stolen session cannot do it.
+func (s *gymlogServer) handleDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	if !s.reauthenticate(w, r, user, r.FormValue("password")) {
+		return
+	}
+
+	err := s.userRepository.DisableTOTP(user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"encoding/json"
+	"gymlog/adapters/auth"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loginWithMFA sends the password of the user and returns the token of the second login step.
+func loginWithMFA(t *testing.T, h http.Handler, username string) string {
+	t.Helper()
+
+	rec := serveForm(h, "/api/v1/login", url.Values{"username": {username}, "password": {testPassword}})
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("login %s: got %d %s, want the MFA step", username, rec.Code, rec.Body)
+	}
+	var response mfaRequiredResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if !response.MFARequired || response.MFAToken == "" {
+		t.Fatalf("got login response %+v", response)
+	}
+	return response.MFAToken
+}
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+
+	code, err := auth.TOTPCode(secret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("enroll: got %d %s", rec.Code, rec.Body)
+	}
+	var enrollment enrollTOTPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &enrollment); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enrollment.OTPAuthURI, "otpauth://totp/gymlog:alice?") {
+		t.Fatalf("got otpauth URI %s", enrollment.OTPAuthURI)
+	}
+
+	// Until it is confirmed the password is enough.
+	login(t, h, "alice")
+
+	step := auth.TOTPStep(time.Now())
+	if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp/confirm?code=abcdef", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("confirm with a wrong code: got %d %s", rec.Code, rec.Body)
+	}
+	rec = alice.do(h, http.MethodPost, "/api/v1/mfa/totp/confirm?code="+totpCode(t, enrollment.Secret, step), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm: got %d %s", rec.Code, rec.Body)
+	}
+	var recovery recoveryCodesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &recovery); err != nil {
+		t.Fatal(err)
+	}
+	if len(recovery.RecoveryCodes) != 10 {
+		t.Fatalf("got %d recovery codes", len(recovery.RecoveryCodes))
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("enroll again: got %d %s", rec.Code, rec.Body)
+	}
+
+	// The code used to confirm cannot be used again, the next one logs in.
+	mfaToken := loginWithMFA(t, h, "alice")
+	if rec := serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {mfaToken}, "code": {totpCode(t, enrollment.Secret, step)}}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("replayed code: got %d %s", rec.Code, rec.Body)
+	}
+	rec = serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {mfaToken}, "code": {totpCode(t, enrollment.Secret, step+1)}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login with code: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {mfaToken}, "code": {recovery.RecoveryCodes[1]}}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("reused MFA token: got %d %s", rec.Code, rec.Body)
+	}
+
+	// Recovery codes work once, in any case.
+	mfaToken = loginWithMFA(t, h, "alice")
+	code := strings.ToUpper(recovery.RecoveryCodes[0])
+	if rec := serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {mfaToken}, "code": {code}}); rec.Code != http.StatusOK {
+		t.Fatalf("login with recovery code: got %d %s", rec.Code, rec.Body)
+	}
+	mfaToken = loginWithMFA(t, h, "alice")
+	if rec := serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {mfaToken}, "code": {code}}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("reused recovery code: got %d %s", rec.Code, rec.Body)
+	}
+
+	// Too many wrong codes end the login, and count as failed logins.
+	for range 5 {
+		serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {mfaToken}, "code": {"abcdef"}})
+	}
+	if rec := serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {mfaToken}, "code": {recovery.RecoveryCodes[2]}}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("login after too many wrong codes: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {testPassword}}); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("login after too many wrong codes: got %d %s, want a lockout", rec.Code, rec.Body)
+	}
+
+	// Nor can the session guess the password to turn it off meanwhile.
+	if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp/disable?password="+testPassword, ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("disable during the lockout: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestLoginReplacesPendingMFA(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp", "")
+	var enrollment enrollTOTPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &enrollment); err != nil {
+		t.Fatal(err)
+	}
+	step := auth.TOTPStep(time.Now())
+	if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp/confirm?code="+totpCode(t, enrollment.Secret, step), ""); rec.Code != http.StatusOK {
+		t.Fatalf("confirm: got %d %s", rec.Code, rec.Body)
+	}
+
+	first := loginWithMFA(t, h, "alice")
+	second := loginWithMFA(t, h, "alice")
+	if rec := serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {first}, "code": {totpCode(t, enrollment.Secret, step+1)}}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("replaced MFA token: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/login/mfa", url.Values{"mfa_token": {second}, "code": {totpCode(t, enrollment.Secret, step+1)}}); rec.Code != http.StatusOK {
+		t.Fatalf("latest MFA token: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestDisableTOTP(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp", "")
+	var enrollment enrollTOTPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &enrollment); err != nil {
+		t.Fatal(err)
+	}
+	code := totpCode(t, enrollment.Secret, auth.TOTPStep(time.Now()))
+	if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp/confirm?code="+code, ""); rec.Code != http.StatusOK {
+		t.Fatalf("confirm: got %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp/disable?password=wrong", ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("disable with a wrong password: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp/disable?password="+testPassword, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("disable: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp", ""); rec.Code != http.StatusOK {
+		t.Fatalf("enroll after disabling: got %d %s", rec.Code, rec.Body)
+	}
+
+	// Wrong passwords count as failed logins of the account, with the same lockout.
+	for range 5 {
+		if rec := alice.do(h, http.MethodPost, "/api/v1/mfa/totp/disable?password=wrong", ""); rec.Code != http.StatusForbidden {
+			t.Fatalf("wrong password: got %d %s", rec.Code, rec.Body)
+		}
+	}
+	rec = alice.do(h, http.MethodPost, "/api/v1/mfa/totp/disable?password="+testPassword, "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("right password during the lockout: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {testPassword}}); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("login during the lockout: got %d %s", rec.Code, rec.Body)
+	}
+}
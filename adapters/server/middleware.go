@@ -0,0 +1,394 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gymlog/adapters/application"
+	"gymlog/domain"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// middleware wraps a handler with behaviour shared by many routes.
+type middleware func(http.Handler) http.Handler
+
+// chain wraps the handler with the middlewares, the first one runs first.
+func chain(handler http.Handler, middlewares ...middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// routeGroup registers routes that share the same middlewares.
+type routeGroup struct {
+	mux         *http.ServeMux
+	middlewares []middleware
+}
+
+func newRouteGroup(mux *http.ServeMux, middlewares ...middleware) routeGroup {
+	return routeGroup{mux: mux, middlewares: middlewares}
+}
+
+// HandleFunc registers the handler for a ServeMux pattern like "GET /api/v1/routines/{id}".
+func (g routeGroup) HandleFunc(pattern string, handler http.HandlerFunc) {
+	g.mux.Handle(pattern, chain(handler, g.middlewares...))
+}
+
+// legacyDeprecatedAt is when the routes before /api/v1 were deprecated, sent in the Deprecation
+// header of their responses as defined by RFC 9745.
+var legacyDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// Deprecated registers a legacy route that has been replaced by successor, a path that can use
+// the {id} of the pattern.
+func (g routeGroup) Deprecated(pattern, successor string, handler http.HandlerFunc) {
+	middlewares := append([]middleware{deprecated(successor)}, g.middlewares...)
+	g.mux.Handle(pattern, chain(handler, middlewares...))
+}
+
+// deprecated marks every response, errors included, as deprecated and links to the successor.
+func deprecated(successor string) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			link := strings.ReplaceAll(successor, "{id}", url.PathEscape(r.PathValue("id")))
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()))
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, link))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type contextKey int
+
+const (
+	authContextKey contextKey = iota
+	requestIDContextKey
+)
+
+// requestAuth is how a request was authenticated, with a session or with a personal access token.
+type requestAuth struct {
+	user    domain.User
+	session domain.UserSession
+	// accessToken is set instead of session for requests with an access token.
+	accessToken *domain.AccessToken
+	// fromCookie is set when the session token came from the cookie, so the CSRF token must be checked.
+	fromCookie bool
+}
+
+// requireAuth rejects the requests without a valid session or access token, the user and
+// credentials of the others are added to the request context.
+func (s *gymlogServer) requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, err := s.authenticate(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authContextKey, auth)))
+	})
+}
+
+// optionalAuth adds the user and session to the request context when the request is fully
+// authenticated, including its CSRF token, and lets every other request through anonymously.
+func (s *gymlogServer) optionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, err := s.authenticate(r)
+		if err != nil && !errors.Is(err, errUnauthorized) {
+			writeError(w, r, err)
+			return
+		}
+		if err == nil && validCSRF(r, auth) {
+			if err := s.touch(r, auth); err != nil {
+				writeError(w, r, err)
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), authContextKey, auth))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireCSRF rejects the requests authenticated with the session cookie that do not repeat the
+// CSRF token in the X-CSRF-Token header. Browsers send cookies on their own, even from other sites,
+// but never that header, so bearer tokens need no CSRF token. It must run after requireAuth,
+// like the other middlewares that read the authentication of the request.
+func requireCSRF(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, ok := r.Context().Value(authContextKey).(requestAuth)
+		if !ok || !validCSRF(r, auth) {
+			writeError(w, r, errInvalidCSRF)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func validCSRF(r *http.Request, auth requestAuth) bool {
+	if !auth.fromCookie {
+		return true
+	}
+	csrf := r.Header.Get("X-CSRF-Token")
+	return csrf != "" && csrf == auth.session.CSRFToken
+}
+
+// requireScope rejects the requests of read only access tokens that could change data.
+func requireScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, _ := r.Context().Value(authContextKey).(requestAuth)
+		readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead
+		if auth.accessToken != nil && !auth.accessToken.CanWrite() && !readOnly {
+			writeError(w, r, errReadOnlyToken)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireSession rejects the requests authenticated with an access token, for the routes that
+// manage the sessions and tokens of the user.
+func requireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, _ := r.Context().Value(authContextKey).(requestAuth)
+		if auth.accessToken != nil {
+			writeError(w, r, errSessionRequired)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireVerifiedEmail rejects the requests of users that did not verify their email yet, when
+// the server is configured to require it. It must run after requireAuth.
+func (s *gymlogServer) requireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := userFromContext(r.Context())
+		if s.config.RequireVerifiedEmail && !user.EmailVerified() {
+			writeError(w, r, errEmailNotVerified)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// touchCredentials records the use of the session or access token of the request, renewing
+// sessions. It runs after the CSRF check so that forged requests cannot keep a session alive.
+func (s *gymlogServer) touchCredentials(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, _ := r.Context().Value(authContextKey).(requestAuth)
+		if err := s.touch(r, auth); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *gymlogServer) touch(r *http.Request, auth requestAuth) error {
+	if auth.accessToken != nil {
+		return s.userRepository.TouchAccessToken(*auth.accessToken)
+	}
+	return s.userRepository.TouchSession(auth.session, clientIP(r))
+}
+
+// authenticate resolves the user of a request from a personal access token or a session token,
+// sent either as an "Authorization: Bearer" header or as the session_token cookie.
+func (s *gymlogServer) authenticate(r *http.Request) (requestAuth, error) {
+	sessionToken, fromCookie := bearerToken(r), false
+	if strings.HasPrefix(sessionToken, domain.AccessTokenPrefix) {
+		return s.authenticateAccessToken(sessionToken)
+	}
+	if sessionToken == "" {
+		cookie, err := r.Cookie("session_token")
+		if err != nil || cookie.Value == "" {
+			return requestAuth{}, errUnauthorized
+		}
+		sessionToken, fromCookie = cookie.Value, true
+	}
+
+	session, err := s.userRepository.Session(sessionToken)
+	if errors.Is(err, application.ErrSessionNotFound) {
+		return requestAuth{}, errUnauthorized
+	}
+	if err != nil {
+		return requestAuth{}, err
+	}
+
+	user, err := s.userRepository.User(session.UserID)
+	if errors.Is(err, application.ErrUserNotFound) {
+		return requestAuth{}, errUnauthorized
+	}
+	if err != nil {
+		return requestAuth{}, err
+	}
+	return requestAuth{user: user, session: session, fromCookie: fromCookie}, nil
+}
+
+func (s *gymlogServer) authenticateAccessToken(secret string) (requestAuth, error) {
+	token, err := s.userRepository.AccessToken(secret)
+	if errors.Is(err, application.ErrAccessTokenNotFound) {
+		return requestAuth{}, errUnauthorized
+	}
+	if err != nil {
+		return requestAuth{}, err
+	}
+
+	user, err := s.userRepository.User(token.UserID)
+	if errors.Is(err, application.ErrUserNotFound) {
+		return requestAuth{}, errUnauthorized
+	}
+	if err != nil {
+		return requestAuth{}, err
+	}
+	return requestAuth{user: user, accessToken: &token}, nil
+}
+
+// bearerToken returns the token of the Authorization header, or "" without one.
+func bearerToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// userFromContext returns the authenticated user of the request context.
+func userFromContext(ctx context.Context) (domain.User, bool) {
+	auth, ok := ctx.Value(authContextKey).(requestAuth)
+	return auth.user, ok
+}
+
+// sessionFromContext returns the session the request was authenticated with, requests with an
+// access token have none.
+func sessionFromContext(ctx context.Context) (domain.UserSession, bool) {
+	auth, ok := ctx.Value(authContextKey).(requestAuth)
+	return auth.session, ok && auth.accessToken == nil
+}
+
+// validRequestID limits the request IDs accepted from clients, so they are safe to log.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// withRequestID gives every request an ID, taken from the X-Request-ID header when the client
+// sends a valid one, and returns it in the X-Request-ID response header.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-ID")
+		if !validRequestID.MatchString(requestID) {
+			var err error
+			requestID, err = generateToken(12)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+		}
+		w.Header().Set("X-Request-ID", requestID)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey, requestID)))
+	})
+}
+
+// requestIDFromContext returns the ID of the request, or "" outside withRequestID.
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// logRequests logs the method, path, status and duration of every request.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		log.Printf("[%s] %s %s %d %s", requestIDFromContext(r.Context()), r.Method, r.URL.Path, recorder.status, time.Since(start))
+	})
+}
+
+// recoverPanics turns a panic in a handler into a 500 response instead of a dropped connection.
+func recoverPanics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+			log.Printf("[%s] panic: %v\n%s", requestIDFromContext(r.Context()), err, debug.Stack())
+			writeProblem(w, r, http.StatusInternalServerError, "internal_error", "internal server error")
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withProblems answers the requests that match no route with a problem, ServeMux answers them
+// in plain text. Redirects of ServeMux, like adding a trailing slash, are kept.
+func withProblems(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		unmatched := &unmatchedWriter{ResponseWriter: w}
+		mux.ServeHTTP(unmatched, r)
+		switch unmatched.status {
+		case http.StatusNotFound:
+			writeProblem(w, r, http.StatusNotFound, "route_not_found", "no route matches the path")
+		case http.StatusMethodNotAllowed:
+			// ServeMux already set the Allow header
+			writeProblem(w, r, http.StatusMethodNotAllowed, "method_not_allowed", r.Method+" is not allowed on the path")
+		}
+	})
+}
+
+// unmatchedWriter drops the plain text errors of ServeMux and lets any other response through.
+type unmatchedWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *unmatchedWriter) WriteHeader(status int) {
+	if status == http.StatusNotFound || status == http.StatusMethodNotAllowed {
+		w.status = status
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *unmatchedWriter) Write(b []byte) (int, error) {
+	if w.status != 0 {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
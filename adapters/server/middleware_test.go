@@ -0,0 +1,85 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoutesMatchMethods(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"wrong method", http.MethodPatch, "/api/v1/routines", http.StatusMethodNotAllowed},
+		{"wrong method before auth", http.MethodPost, "/getroutines", http.StatusMethodNotAllowed},
+		{"unknown action", http.MethodPost, "/api/v1/workouts/1/restart", http.StatusNotFound},
+		{"invalid ID", http.MethodGet, "/api/v1/routines/abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := alice.do(h, tt.method, tt.path, ""); rec.Code != tt.want {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticatedRoutesRequireCSRF(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	withoutCSRF := alice
+	withoutCSRF.csrf = ""
+	for _, path := range []string{"/api/v1/routines", "/api/v1/workouts", "/api/v1/sessions"} {
+		if rec := withoutCSRF.do(h, http.MethodGet, path, ""); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s without CSRF token: got %d %s", path, rec.Code, rec.Body)
+		}
+	}
+	if rec := withoutCSRF.do(h, http.MethodGet, "/api/v1/exercises", ""); rec.Code != http.StatusOK {
+		t.Fatalf("public route without CSRF token: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	h := newTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Header().Get("X-Request-ID") == "" {
+		t.Fatal("response has no request ID")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("X-Request-ID", "client-id-1")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Request-ID"); got != "client-id-1" {
+		t.Fatalf("got request ID %q, want the one of the client", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("X-Request-ID", "bad id\n")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Request-ID"); got == "" || got == "bad id\n" {
+		t.Fatalf("got request ID %q for an invalid one", got)
+	}
+}
+
+func TestRecoverPanics(t *testing.T) {
+	h := chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), withRequestID, logRequests, recoverPanics)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"gymlog/domain"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// handleRequestPasswordReset emails a password reset token to the "email" of the form. The
+// response is the same whether the email has an account or not, so the email is sent in the
+// background and its errors are only logged.
+func (s *gymlogServer) handleRequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	email := strings.TrimSpace(r.FormValue("email"))
+	if email == "" {
+		writeError(w, r, requiredField("email", email))
+		return
+	}
+
+	// Unknown emails would get their answer sooner than the ones that wait for the mailer.
+	ctx := context.WithoutCancel(r.Context())
+	go func() {
+		if err := s.userRepository.RequestPasswordReset(ctx, email); err != nil {
+			log.Printf("[%s] sending a password reset: %v", requestIDFromContext(ctx), err)
+		}
+	}()
+
+	writeMessage(w, r, "If the email has an account, a password reset token was sent to it")
+}
+
+// handleConfirmPasswordReset sets the "password" of the form as the new password of the user of
+// the reset "token", logging them out of every session.
+func (s *gymlogServer) handleConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	token := r.FormValue("token")
+	password := r.FormValue("password")
+	if token == "" || password == "" {
+		writeError(w, r, errors.Join(requiredField("token", token), requiredField("password", password)))
+		return
+	}
+	if err := domain.ValidatePassword(password); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = s.userRepository.ResetPassword(token, passwordHash)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMessage(w, r, "Password changed, log in again")
+}
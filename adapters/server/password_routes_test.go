@@ -0,0 +1,88 @@
+package server
+
+import (
+	"gymlog/adapters/mailer"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPasswordReset(t *testing.T) {
+	h, mails := newTestHandlerWithMailer(t)
+	alice := registerAndLogin(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+
+	// Skip the email verification emails of the registrations.
+	sent := len(mails.Messages())
+
+	unknown := serveForm(h, "/api/v1/password/reset", url.Values{"email": {"nobody@gymlog.test"}})
+	if unknown.Code != http.StatusOK {
+		t.Fatalf("reset unknown email: got %d %s", unknown.Code, unknown.Body)
+	}
+	rec := serveForm(h, "/api/v1/password/reset", url.Values{"email": {"alice@gymlog.test"}})
+	if rec.Code != http.StatusOK || rec.Body.String() != unknown.Body.String() {
+		t.Fatalf("request reset: got %d %s, want the same answer as an unknown email", rec.Code, rec.Body)
+	}
+	messages := waitForEmails(t, mails, sent+1)[sent:]
+	if len(messages) != 1 || messages[0].To != "alice@gymlog.test" {
+		t.Fatalf("got emails %+v", messages)
+	}
+	// Asking again right away is answered the same but sends nothing.
+	if rec := serveForm(h, "/api/v1/password/reset", url.Values{"email": {"alice@gymlog.test"}}); rec.Code != http.StatusOK {
+		t.Fatalf("request reset again: got %d %s", rec.Code, rec.Body)
+	}
+	token := tokenFromEmail(t, messages[0].Body)
+
+	if rec := serveForm(h, "/api/v1/password/reset/confirm", url.Values{"token": {"nope"}, "password": {"new secret"}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("confirm with unknown token: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/password/reset/confirm", url.Values{"token": {token}, "password": {"new secret"}}); rec.Code != http.StatusOK {
+		t.Fatalf("confirm reset: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/password/reset/confirm", url.Values{"token": {token}, "password": {"yet another secret"}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("token used twice: got %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := alice.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("session after reset: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := bob.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusOK {
+		t.Fatalf("other user after reset: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {testPassword}}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("login with old password: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {"new secret"}}); rec.Code != http.StatusOK {
+		t.Fatalf("login with new password: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+// waitForEmails waits until the mailer sent at least n emails, as some are sent in the
+// background after the response, and returns them.
+func waitForEmails(t *testing.T, mails *mailer.LogMailer, n int) []mailer.Message {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if messages := mails.Messages(); len(messages) >= n {
+			return messages
+		}
+	}
+	t.Fatalf("sent %d emails, want %d", len(mails.Messages()), n)
+	return nil
+}
+
+// tokenFromEmail returns the token of a password reset or email verification email, alone in its line.
+func tokenFromEmail(t *testing.T, body string) string {
+	t.Helper()
+
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "Use this token") && i+2 < len(lines) {
+			return lines[i+2]
+		}
+	}
+	t.Fatalf("no token in email %q", body)
+	return ""
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// messageResponse is the body of the successful responses that have nothing else to return.
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+// writeJSON answers with the value encoded as JSON.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		// The status is already sent, the client gets a truncated body.
+		log.Printf("[%s] encode response: %v", requestIDFromContext(r.Context()), err)
+	}
+}
+
+// writeCreated answers 201 with the new resource and its URL in the Location header.
+func writeCreated(w http.ResponseWriter, r *http.Request, location string, v any) {
+	w.Header().Set("Location", location)
+	writeJSON(w, r, http.StatusCreated, v)
+}
+
+func writeMessage(w http.ResponseWriter, r *http.Request, message string) {
+	writeJSON(w, r, http.StatusOK, messageResponse{Message: message})
+}
@@ -2,159 +2,188 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"gymlog/domain"
 	"net/http"
-	"strconv"
-	"strings"
 )
 
-// handleGetExercises handles the GET request for the exercises.
-func (s *gymlogServer) handleGetExercises(w http.ResponseWriter, r *http.Request) {
+// handleSetRoutine creates a routine for a user and returns it with its ID.
+func (s *gymlogServer) handleSetRoutine(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
 
-	if r.Method != http.MethodGet {
-		http.Error(w, "Must be a GET request", http.StatusMethodNotAllowed)
+	var routineRequest postRoutineRequest
+	err := json.NewDecoder(r.Body).Decode(&routineRequest)
+	if err != nil {
+		writeError(w, r, errInvalidBody)
 		return
 	}
 
-	exercises, err := s.routineRepository.Exercises()
+	exerciseDetails := routineRequestToExerciseDetails(routineRequest)
+
+	routine, err := domain.CreateRoutine(routineRequest.Name, routineRequest.Description, exerciseDetails)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(exercises)
+	routine, err = s.routineRepository.SetRoutine(user.ID, routine)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
+
+	writeCreated(w, r, fmt.Sprintf(apiV1+"/routines/%d", routine.ID), routine)
 }
 
-// handleSetRoutine sets a routine for a user.
-func (s *gymlogServer) handleSetRoutine(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Must be a POST request", http.StatusMethodNotAllowed)
+// handleGetRoutines handles the GET request for the routines.
+func (s *gymlogServer) handleGetRoutines(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+
+	routines, err := s.routineRepository.GetRoutines(user.ID)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := s.Authorize(r); err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	writeJSON(w, r, http.StatusOK, routines)
+}
+
+// handleGetRoutine handles the GET request for a specific routine by ID.
+func (s *gymlogServer) handleGetRoutine(w http.ResponseWriter, r *http.Request) {
+	routineID, err := idFromPath(r, "routine")
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	username := r.FormValue("username")
-	user, err := s.userRepository.Users(username)
+	user, _ := userFromContext(r.Context())
+
+	// Routines of other users that were not shared are reported as not found, never as forbidden.
+	routine, err := s.routineRepository.GetRoutine(user.ID, routineID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	if len(user) == 0 {
-		http.Error(w, "User not found", http.StatusNotFound)
+
+	writeJSON(w, r, http.StatusOK, routine)
+}
+
+// handleUpdateRoutine replaces a routine of the user with the one in the request body.
+func (s *gymlogServer) handleUpdateRoutine(w http.ResponseWriter, r *http.Request) {
+	routineID, err := idFromPath(r, "routine")
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
+	user, _ := userFromContext(r.Context())
+
 	var routineRequest postRoutineRequest
 	err = json.NewDecoder(r.Body).Decode(&routineRequest)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, errInvalidBody)
 		return
 	}
 
-	exerciseDetails := routineRequestToExerciseDetails(routineRequest)
-
-	routine, err := domain.CreateRoutine(routineRequest.Name, routineRequest.Description, exerciseDetails)
+	routine, err := domain.CreateRoutine(routineRequest.Name, routineRequest.Description, routineRequestToExerciseDetails(routineRequest))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
+	routine.ID = routineID
 
-	err = s.routineRepository.SetRoutine(user[0].ID, routine)
+	routine, err = s.routineRepository.UpdateRoutine(user.ID, routine)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	writeJSON(w, r, http.StatusOK, routine)
 }
 
-// handleGetRoutines handles the GET request for the routines.
-func (s *gymlogServer) handleGetRoutines(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Must be a GET request", http.StatusMethodNotAllowed)
+// handlePatchRoutine partially updates a routine of the user, for example to rename it or reorder its exercises.
+func (s *gymlogServer) handlePatchRoutine(w http.ResponseWriter, r *http.Request) {
+	routineID, err := idFromPath(r, "routine")
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := s.Authorize(r); err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
+	user, _ := userFromContext(r.Context())
 
-	username := r.FormValue("username")
-	user, err := s.userRepository.Users(username)
+	var patchRequest patchRoutineRequest
+	err = json.NewDecoder(r.Body).Decode(&patchRequest)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if len(user) == 0 {
-		http.Error(w, "User not found", http.StatusNotFound)
+		writeError(w, r, errInvalidBody)
 		return
 	}
 
-	routines, err := s.routineRepository.GetRoutines(user[0].ID)
+	routine, err := s.routineRepository.PatchRoutine(user.ID, routineID, patchRequest.toDomain())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(routines)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.WriteHeader(http.StatusOK)
+	writeJSON(w, r, http.StatusOK, routine)
 }
 
-// handleGetRoutine handles the GET request for a specific routine by ID.
-func (s *gymlogServer) handleGetRoutine(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Must be a GET request", http.StatusMethodNotAllowed)
+// handleDeleteRoutine deletes a routine of the user.
+func (s *gymlogServer) handleDeleteRoutine(w http.ResponseWriter, r *http.Request) {
+	routineID, err := idFromPath(r, "routine")
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := s.Authorize(r); err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
+	user, _ := userFromContext(r.Context())
 
-	// Extract routine ID from URL path: /routine/{id}
-	pathParts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
-	if len(pathParts) < 2 {
-		http.Error(w, "Routine ID is required", http.StatusBadRequest)
+	err = s.routineRepository.DeleteRoutine(user.ID, routineID)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	routineID, err := strconv.Atoi(pathParts[1])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleShareRoutine shares (POST) or stops sharing (DELETE) a routine of the user with the user
+// in the "share_with" field of the body.
+func (s *gymlogServer) handleShareRoutine(w http.ResponseWriter, r *http.Request) {
+	routineID, err := idFromPath(r, "routine")
 	if err != nil {
-		http.Error(w, "Invalid routine ID", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	routine, err := s.routineRepository.GetRoutine(routineID)
+	user, _ := userFromContext(r.Context())
+
+	var shareRequest shareRoutineRequest
+	err = json.NewDecoder(r.Body).Decode(&shareRequest)
 	if err != nil {
-		http.Error(w, "Routine not found", http.StatusNotFound)
+		writeError(w, r, errInvalidBody)
+		return
+	}
+	if err := requiredField("share_with", shareRequest.ShareWith); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(routine)
+	// Unknown usernames get the same answer as the others, so sharing cannot tell which users exist
+	if r.Method == http.MethodPost {
+		err = s.routineRepository.ShareRoutine(user.ID, routineID, shareRequest.ShareWith)
+	} else {
+		err = s.routineRepository.UnshareRoutine(user.ID, routineID, shareRequest.ShareWith)
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type shareRoutineRequest struct {
+	ShareWith string `json:"share_with"`
 }
 
 type postRoutineRequest struct {
@@ -169,6 +198,27 @@ type postRoutineExercise struct {
 	Reps int `json:"reps"`
 }
 
+// patchRoutineRequest only changes the fields present in the body.
+type patchRoutineRequest struct {
+	Name        *string                `json:"name"`
+	Description *string                `json:"description"`
+	Exercises   *[]postRoutineExercise `json:"exercises"`
+	Order       []int                  `json:"order"`
+}
+
+func (p patchRoutineRequest) toDomain() domain.RoutinePatch {
+	patch := domain.RoutinePatch{
+		Name:        p.Name,
+		Description: p.Description,
+		Order:       p.Order,
+	}
+	if p.Exercises != nil {
+		exerciseDetails := routineRequestToExerciseDetails(postRoutineRequest{Exercises: *p.Exercises})
+		patch.Exercises = &exerciseDetails
+	}
+	return patch
+}
+
 func routineRequestToExerciseDetails(request postRoutineRequest) []domain.ExerciseDetail {
 	exerciseDetails := []domain.ExerciseDetail{}
 	for _, exercise := range request.Exercises {
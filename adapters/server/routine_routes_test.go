@@ -0,0 +1,331 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gymlog/adapters/application"
+	"gymlog/adapters/mailer"
+	"gymlog/adapters/storage"
+	"gymlog/domain"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newTestHandler returns the server handlers backed by a fresh in-memory storage.
+func newTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	h, _ := newTestHandlerWithMailer(t)
+	return h
+}
+
+// newTestHandlerWithMailer also returns the mailer, to read the emails the server sends.
+func newTestHandlerWithMailer(t *testing.T) (http.Handler, *mailer.LogMailer) {
+	t.Helper()
+
+	return newTestHandlerWithConfig(t, Config{})
+}
+
+func newTestHandlerWithConfig(t *testing.T, config Config) (http.Handler, *mailer.LogMailer) {
+	t.Helper()
+
+	store, err := storage.NewMemoryStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mails := mailer.NewLogMailer(io.Discard)
+	s := NewServer(application.NewGymRepository(store), application.NewUserRepo(store, domain.DefaultSessionPolicy, mails), config)
+	return s.loadHandlers(), mails
+}
+
+// testUser is a registered and logged in user that sends authorized requests.
+type testUser struct {
+	username     string
+	cookies      []*http.Cookie
+	csrf         string
+	sessionToken string
+}
+
+// testPassword is the password of every user registered by the tests.
+const testPassword = "leg-day-every-day"
+
+func registerAndLogin(t *testing.T, h http.Handler, username string) testUser {
+	t.Helper()
+
+	form := url.Values{"username": {username}, "email": {username + "@gymlog.test"}, "password": {testPassword}}
+	rec := serveForm(h, "/api/v1/register", form)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register %s: got %d %s", username, rec.Code, rec.Body)
+	}
+	return login(t, h, username)
+}
+
+// login starts a new session of an already registered user, as if from another device.
+func login(t *testing.T, h http.Handler, username string) testUser {
+	t.Helper()
+
+	rec := serveForm(h, "/api/v1/login", url.Values{"username": {username}, "password": {testPassword}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login %s: got %d %s", username, rec.Code, rec.Body)
+	}
+
+	user := testUser{username: username, cookies: rec.Result().Cookies()}
+	for _, cookie := range user.cookies {
+		switch cookie.Name {
+		case "csrf_token":
+			user.csrf = cookie.Value
+		case "session_token":
+			user.sessionToken = cookie.Value
+		}
+	}
+	return user
+}
+
+func serveForm(h http.Handler, path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// do sends a JSON request as the user, authenticated with their session cookie and CSRF token.
+func (u testUser) do(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-CSRF-Token", u.csrf)
+	for _, cookie := range u.cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// createRoutine creates a routine for the user and returns its ID.
+func (u testUser) createRoutine(t *testing.T, h http.Handler, name string) int {
+	t.Helper()
+
+	body := fmt.Sprintf(`{"name":%q,"exercises":[{"id":1,"sets":3,"reps":8},{"id":2}]}`, name)
+	rec := u.do(h, http.MethodPost, "/api/v1/routines", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create routine: got %d %s", rec.Code, rec.Body)
+	}
+	var routine domain.Routine
+	if err := json.NewDecoder(rec.Body).Decode(&routine); err != nil {
+		t.Fatal(err)
+	}
+	if location := rec.Header().Get("Location"); location != fmt.Sprintf("/api/v1/routines/%d", routine.ID) {
+		t.Fatalf("created routine %+v at %q", routine, location)
+	}
+	if routine.Name != name || len(routine.Exercises) != 2 || routine.Exercises[0].ID != 1 {
+		t.Fatalf("got created routine %+v", routine)
+	}
+	return routine.ID
+}
+
+func (u testUser) routines(t *testing.T, h http.Handler) []domain.Routine {
+	t.Helper()
+
+	rec := u.do(h, http.MethodGet, "/api/v1/routines", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get routines: got %d %s", rec.Code, rec.Body)
+	}
+	var routines []domain.Routine
+	if err := json.NewDecoder(rec.Body).Decode(&routines); err != nil {
+		t.Fatal(err)
+	}
+	return routines
+}
+
+func TestUpdatedRoutinesAreAnsweredAsStored(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	routinePath := fmt.Sprintf("/api/v1/routines/%d", alice.createRoutine(t, h, "push day"))
+
+	for _, step := range []struct{ method, body string }{
+		{http.MethodPut, `{"name":"pull day","exercises":[{"id":3,"sets":4},{"id":1}]}`},
+		{http.MethodPatch, `{"name":"back day","order":[1,0]}`},
+	} {
+		rec := alice.do(h, step.method, routinePath, step.body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: got %d %s", step.method, rec.Code, rec.Body)
+		}
+		if stored := alice.do(h, http.MethodGet, routinePath, ""); rec.Body.String() != stored.Body.String() {
+			t.Fatalf("%s: answered %s, stored %s", step.method, rec.Body, stored.Body)
+		}
+	}
+}
+
+func TestRoutineEndpointsRejectOtherUsers(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+	routineID := alice.createRoutine(t, h, "push day")
+	routinePath := fmt.Sprintf("/api/v1/routines/%d", routineID)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"get", http.MethodGet, routinePath, ""},
+		{"update", http.MethodPut, routinePath, `{"name":"mine now","exercises":[{"id":3}]}`},
+		{"patch", http.MethodPatch, routinePath, `{"name":"mine now"}`},
+		{"reorder", http.MethodPatch, routinePath, `{"order":[1,0]}`},
+		{"delete", http.MethodDelete, routinePath, ""},
+		{"share", http.MethodPost, routinePath + "/share", `{"share_with":"alice"}`},
+		{"unshare", http.MethodDelete, routinePath + "/share", `{"share_with":"alice"}`},
+		{"start workout", http.MethodPost, "/api/v1/workouts", fmt.Sprintf(`{"routine_id":%d}`, routineID)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := bob.do(h, tt.method, tt.path, tt.body)
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, http.StatusNotFound)
+			}
+		})
+	}
+
+	if routines := bob.routines(t, h); len(routines) != 0 {
+		t.Fatalf("bob sees %d routines of alice", len(routines))
+	}
+
+	rec := alice.do(h, http.MethodGet, routinePath, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("owner get: got %d %s", rec.Code, rec.Body)
+	}
+	var routine domain.Routine
+	if err := json.NewDecoder(rec.Body).Decode(&routine); err != nil {
+		t.Fatal(err)
+	}
+	if routine.Name != "push day" || len(routine.Exercises) != 2 || routine.Exercises[0].ID != 1 {
+		t.Fatalf("routine was modified by another user: %+v", routine)
+	}
+}
+
+func TestRoutineEndpointsIgnoreUsername(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+	routineID := alice.createRoutine(t, h, "push day")
+
+	// Bob keeps his own cookies but claims to be alice, the session decides who he is.
+	query := "?username=" + alice.username
+	if rec := bob.do(h, http.MethodGet, fmt.Sprintf("/api/v1/routines/%d", routineID)+query, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("get: got %d %s, want %d", rec.Code, rec.Body, http.StatusNotFound)
+	}
+	if rec := bob.do(h, http.MethodDelete, fmt.Sprintf("/api/v1/routines/%d", routineID)+query, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("delete: got %d %s, want %d", rec.Code, rec.Body, http.StatusNotFound)
+	}
+	if rec := bob.do(h, http.MethodPost, "/api/v1/routines"+query, `{"name":"spam","exercises":[{"id":1}]}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create: got %d %s", rec.Code, rec.Body)
+	}
+
+	if routines := alice.routines(t, h); len(routines) != 1 || routines[0].Name != "push day" {
+		t.Fatalf("alice has routines %+v", routines)
+	}
+	if routines := bob.routines(t, h); len(routines) != 1 || routines[0].Name != "spam" {
+		t.Fatalf("bob has routines %+v", routines)
+	}
+}
+
+func TestSharedRoutineIsReadOnly(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+	routineID := alice.createRoutine(t, h, "push day")
+	routinePath := fmt.Sprintf("/api/v1/routines/%d", routineID)
+
+	if rec := alice.do(h, http.MethodPost, routinePath+"/share", `{"share_with":"bob"}`); rec.Code != http.StatusNoContent {
+		t.Fatalf("share: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := bob.do(h, http.MethodGet, routinePath, ""); rec.Code != http.StatusOK {
+		t.Fatalf("get shared routine: got %d %s", rec.Code, rec.Body)
+	}
+	// Invalid changes are not validated either, the errors would tell bob about the routine.
+	for _, body := range []string{`{"name":"mine now"}`, `{"order":[0]}`, `{"exercises":[{"id":9999}]}`} {
+		if rec := bob.do(h, http.MethodPatch, routinePath, body); rec.Code != http.StatusNotFound {
+			t.Fatalf("patch shared routine with %s: got %d %s", body, rec.Code, rec.Body)
+		}
+	}
+	if rec := bob.do(h, http.MethodPut, routinePath, `{"name":"mine now","exercises":[{"id":9999}]}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("update shared routine: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := bob.do(h, http.MethodDelete, routinePath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("delete shared routine: got %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := alice.do(h, http.MethodDelete, routinePath+"/share", `{"share_with":"bob"}`); rec.Code != http.StatusNoContent {
+		t.Fatalf("unshare: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := bob.do(h, http.MethodGet, routinePath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("get unshared routine: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestShareRoutineDoesNotRevealUsers(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	registerAndLogin(t, h, "bob")
+	routinePath := fmt.Sprintf("/api/v1/routines/%d", alice.createRoutine(t, h, "push day"))
+
+	for _, method := range []string{http.MethodPost, http.MethodDelete} {
+		known := alice.do(h, method, routinePath+"/share", `{"share_with":"bob"}`)
+		unknown := alice.do(h, method, routinePath+"/share", `{"share_with":"nobody"}`)
+		if known.Code != http.StatusNoContent || unknown.Code != known.Code || unknown.Body.String() != known.Body.String() {
+			t.Fatalf("%s: got %d %s for a user and %d %s for nobody", method, known.Code, known.Body, unknown.Code, unknown.Body)
+		}
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"yourself", `{"share_with":"alice"}`, http.StatusBadRequest},
+		{"missing", `{}`, http.StatusBadRequest},
+		{"form value", "share_with=bob", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := alice.do(h, http.MethodPost, routinePath+"/share", tt.body); rec.Code != tt.want {
+			t.Fatalf("%s: got %d %s, want %d", tt.name, rec.Code, rec.Body, tt.want)
+		}
+	}
+}
+
+func TestReorderRoutineWithRepeatedExercise(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	rec := alice.do(h, http.MethodPost, "/api/v1/routines", `{"name":"legs","exercises":[{"id":1,"sets":5,"reps":5},{"id":2},{"id":1,"sets":2,"reps":20}]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: got %d %s", rec.Code, rec.Body)
+	}
+	routinePath := rec.Header().Get("Location")
+
+	rec = alice.do(h, http.MethodPatch, routinePath, `{"order":[2,0,1]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reorder: got %d %s", rec.Code, rec.Body)
+	}
+	var routine domain.Routine
+	if err := json.NewDecoder(rec.Body).Decode(&routine); err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.ExerciseDetail{{ID: 1, Sets: 2, Reps: 20}, {ID: 1, Sets: 5, Reps: 5}, {ID: 2, Sets: 3, Reps: 6}}
+	if fmt.Sprint(routine.Exercises) != fmt.Sprint(want) {
+		t.Fatalf("got exercises %+v, want %+v", routine.Exercises, want)
+	}
+
+	for _, order := range []string{`[0,1]`, `[0,0,1]`, `[0,1,3]`} {
+		if rec := alice.do(h, http.MethodPatch, routinePath, `{"order":`+order+`}`); rec.Code != http.StatusBadRequest {
+			t.Fatalf("reorder with %s: got %d %s", order, rec.Code, rec.Body)
+		}
+	}
+}
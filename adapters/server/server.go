@@ -1,9 +1,12 @@
 package server
 
 import (
+	"context"
+	"fmt"
 	"gymlog/adapters/application"
 	"log"
 	"net/http"
+	"strconv"
 )
 
 // server is the entry point for http requests for swift/nextjs frontend.
@@ -11,14 +14,23 @@ type gymlogServer struct {
 	server            *http.Server
 	routineRepository application.RoutineRepository
 	userRepository    application.UserRepository
+	config            Config
+}
+
+// Config holds the options of the server.
+type Config struct {
+	// RequireVerifiedEmail blocks the actions that reach other users, like sharing routines,
+	// until the user verifies their email.
+	RequireVerifiedEmail bool
 }
 
 // NewServer is the constructor for the server.
-func NewServer(routineRepository application.RoutineRepository, userRepository application.UserRepository) *gymlogServer {
+func NewServer(routineRepository application.RoutineRepository, userRepository application.UserRepository, config Config) *gymlogServer {
 
 	s := &gymlogServer{
 		routineRepository: routineRepository,
 		userRepository:    userRepository,
+		config:            config,
 	}
 
 	s.server = &http.Server{
@@ -28,21 +40,87 @@ func NewServer(routineRepository application.RoutineRepository, userRepository a
 	return s
 }
 
+// apiV1 prefixes the routes of the first version of the API.
+const apiV1 = "/api/v1"
+
 // loadHandlers loads all the handlers for the server.
 func (s *gymlogServer) loadHandlers() http.Handler {
-	handler := http.NewServeMux()
-	handler.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("Hello, World!"))
+	mux := http.NewServeMux()
+
+	// Public routes, anyone can call them.
+	public := newRouteGroup(mux)
+	public.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, r, http.StatusOK, healthResponse{Status: "ok"})
 	})
-	handler.HandleFunc("/exercises", s.handleGetExercises)
-	handler.HandleFunc("/routines", s.handleSetRoutine)
-	handler.HandleFunc("/getroutines", s.handleGetRoutines)
-	handler.HandleFunc("/routine/", s.handleGetRoutine)
-	handler.HandleFunc("/register", s.handleRegister)
-	handler.HandleFunc("/login", s.handleLogin)
-	handler.HandleFunc("/logout", s.handleLogout)
-	return handler
+	public.HandleFunc("POST "+apiV1+"/register", s.handleRegister)
+	public.HandleFunc("POST "+apiV1+"/login", s.handleLogin)
+	public.HandleFunc("POST "+apiV1+"/login/mfa", s.handleLoginMFA)
+	public.HandleFunc("POST "+apiV1+"/password/reset", s.handleRequestPasswordReset)
+	public.HandleFunc("POST "+apiV1+"/password/reset/confirm", s.handleConfirmPasswordReset)
+	public.HandleFunc("POST "+apiV1+"/email/verify", s.handleVerifyEmail)
+
+	// Public routes that also know the user when the request is authenticated.
+	optional := newRouteGroup(mux, s.optionalAuth)
+	optional.HandleFunc("GET "+apiV1+"/exercises", s.handleGetExercises)
+
+	// Routes for logged in users and access tokens, read only tokens can only GET.
+	authenticated := newRouteGroup(mux, s.requireAuth, requireCSRF, requireScope, s.touchCredentials)
+	authenticated.HandleFunc("GET "+apiV1+"/me", s.handleGetMe)
+	authenticated.HandleFunc("POST "+apiV1+"/exercises", s.handleCreateExercise)
+	authenticated.HandleFunc("GET "+apiV1+"/exercises/{id}", s.handleGetExercise)
+	authenticated.HandleFunc("PUT "+apiV1+"/exercises/{id}", s.handleUpdateExercise)
+	authenticated.HandleFunc("DELETE "+apiV1+"/exercises/{id}", s.handleDeleteExercise)
+	authenticated.HandleFunc("GET "+apiV1+"/routines", s.handleGetRoutines)
+	authenticated.HandleFunc("POST "+apiV1+"/routines", s.handleSetRoutine)
+	authenticated.HandleFunc("GET "+apiV1+"/routines/{id}", s.handleGetRoutine)
+	authenticated.HandleFunc("PUT "+apiV1+"/routines/{id}", s.handleUpdateRoutine)
+	authenticated.HandleFunc("PATCH "+apiV1+"/routines/{id}", s.handlePatchRoutine)
+	authenticated.HandleFunc("DELETE "+apiV1+"/routines/{id}", s.handleDeleteRoutine)
+	authenticated.HandleFunc("DELETE "+apiV1+"/routines/{id}/share", s.handleShareRoutine)
+	authenticated.HandleFunc("GET "+apiV1+"/workouts", s.handleGetWorkouts)
+	authenticated.HandleFunc("POST "+apiV1+"/workouts", s.handleStartWorkout)
+	authenticated.HandleFunc("GET "+apiV1+"/workouts/{id}", s.handleGetWorkout)
+	authenticated.HandleFunc("POST "+apiV1+"/workouts/{id}/sets", s.handleAddWorkoutSet)
+	authenticated.HandleFunc("POST "+apiV1+"/workouts/{id}/finish", s.handleFinishWorkout)
+
+	// Routes that reach other users, which can require a verified email.
+	verified := newRouteGroup(mux, s.requireAuth, requireCSRF, requireScope, s.requireVerifiedEmail, s.touchCredentials)
+	verified.HandleFunc("POST "+apiV1+"/routines/{id}/share", s.handleShareRoutine)
+
+	// Routes that manage the credentials of the user, only for sessions.
+	account := newRouteGroup(mux, s.requireAuth, requireCSRF, requireSession, s.touchCredentials)
+	account.HandleFunc("POST "+apiV1+"/logout", s.handleLogout)
+	account.HandleFunc("GET "+apiV1+"/sessions", s.handleGetSessions)
+	account.HandleFunc("DELETE "+apiV1+"/sessions", s.handleDeleteOtherSessions)
+	account.HandleFunc("DELETE "+apiV1+"/sessions/{id}", s.handleDeleteSession)
+	account.HandleFunc("GET "+apiV1+"/tokens", s.handleGetAccessTokens)
+	account.HandleFunc("POST "+apiV1+"/tokens", s.handleCreateAccessToken)
+	account.HandleFunc("GET "+apiV1+"/tokens/{id}", s.handleGetAccessToken)
+	account.HandleFunc("DELETE "+apiV1+"/tokens/{id}", s.handleDeleteAccessToken)
+	account.HandleFunc("POST "+apiV1+"/email/verify/resend", s.handleResendEmailVerification)
+	account.HandleFunc("POST "+apiV1+"/mfa/totp", s.handleEnrollTOTP)
+	account.HandleFunc("POST "+apiV1+"/mfa/totp/confirm", s.handleConfirmTOTP)
+	account.HandleFunc("POST "+apiV1+"/mfa/totp/disable", s.handleDisableTOTP)
+	account.HandleFunc("POST "+apiV1+"/account/password", s.handleChangePassword)
+	account.HandleFunc("POST "+apiV1+"/account/email", s.handleChangeEmail)
+	account.HandleFunc("POST "+apiV1+"/account/delete", s.handleDeleteAccount)
+	account.HandleFunc("POST "+apiV1+"/account/delete/cancel", s.handleCancelAccountDeletion)
+	account.HandleFunc("GET "+apiV1+"/exports", s.handleGetDataExports)
+	account.HandleFunc("POST "+apiV1+"/exports", s.handleRequestDataExport)
+	account.HandleFunc("GET "+apiV1+"/exports/{id}", s.handleGetDataExport)
+	account.HandleFunc("GET "+apiV1+"/exports/{id}/download", s.handleDownloadDataExport)
+
+	// The routes before /api/v1, kept for the clients that still use them. Their responses say
+	// they are deprecated and link to the route that replaces them.
+	public.Deprecated("POST /register", apiV1+"/register", s.handleRegister)
+	public.Deprecated("POST /login", apiV1+"/login", s.handleLogin)
+	optional.Deprecated("GET /exercises", apiV1+"/exercises", s.handleGetExercises)
+	authenticated.Deprecated("POST /routines", apiV1+"/routines", s.handleSetRoutine)
+	authenticated.Deprecated("GET /getroutines", apiV1+"/routines", s.handleGetRoutines)
+	authenticated.Deprecated("GET /routine/{id}", apiV1+"/routines/{id}", s.handleGetRoutine)
+	account.Deprecated("POST /logout", apiV1+"/logout", s.handleLogout)
+
+	return chain(withProblems(mux), withRequestID, logRequests, recoverPanics)
 }
 
 // Start simply starts the server.
@@ -50,3 +128,22 @@ func (s *gymlogServer) Start() error {
 	log.Println("Starting server on port 6767")
 	return s.server.ListenAndServe()
 }
+
+// Shutdown stops accepting requests and waits for the running ones to finish.
+func (s *gymlogServer) Shutdown(ctx context.Context) error {
+	log.Println("Shutting down server")
+	return s.server.Shutdown(ctx)
+}
+
+// idFromPath returns the numeric {id} of the route pattern, what names it in the error.
+func idFromPath(r *http.Request, what string) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, invalidField("id", fmt.Sprintf("invalid %s ID", what))
+	}
+	return id, nil
+}
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gymlog/domain"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRoutineResource(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	routinePath := fmt.Sprintf("/api/v1/routines/%d", alice.createRoutine(t, h, "push day"))
+	steps := []struct {
+		method string
+		body   string
+		want   int
+	}{
+		{http.MethodGet, "", http.StatusOK},
+		{http.MethodPut, `{"name":"pull day","exercises":[{"id":3,"sets":4,"reps":10}]}`, http.StatusOK},
+		{http.MethodPatch, `{"name":"back day"}`, http.StatusOK},
+		{http.MethodDelete, "", http.StatusNoContent},
+		{http.MethodGet, "", http.StatusNotFound},
+	}
+	for _, step := range steps {
+		if rec := alice.do(h, step.method, routinePath, step.body); rec.Code != step.want {
+			t.Fatalf("%s %s: got %d %s, want %d", step.method, routinePath, rec.Code, rec.Body, step.want)
+		}
+	}
+}
+
+func TestMe(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	rec := alice.do(h, http.MethodGet, "/api/v1/me", "")
+	var me userResponse
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &me) != nil {
+		t.Fatalf("got %d %s", rec.Code, rec.Body)
+	}
+	if me.ID == 0 || me.Username != "alice" || me.Email != "alice@gymlog.test" || me.EmailVerifiedAt != nil {
+		t.Fatalf("got me %+v", me)
+	}
+
+	if rec := (testUser{}).do(h, http.MethodGet, "/api/v1/me", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("without login: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestLegacyRoutesAreDeprecated(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	routineID := alice.createRoutine(t, h, "push day")
+
+	rec := serveForm(h, "/register", url.Values{"username": {"bob"}, "email": {"bob@gymlog.test"}, "password": {testPassword}})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("legacy register: got %d %s", rec.Code, rec.Body)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want int
+		link string
+	}{
+		{"list", "/getroutines", http.StatusOK, "</api/v1/routines>"},
+		{"get", fmt.Sprintf("/routine/%d", routineID), http.StatusOK, fmt.Sprintf("</api/v1/routines/%d>", routineID)},
+		{"error", "/routine/9999", http.StatusNotFound, "</api/v1/routines/9999>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := alice.do(h, http.MethodGet, tt.path, "")
+			if rec.Code != tt.want {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+			if rec.Header().Get("Deprecation") != fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()) {
+				t.Fatalf("got Deprecation %q", rec.Header().Get("Deprecation"))
+			}
+			if link := rec.Header().Get("Link"); link != tt.link+`; rel="successor-version"` {
+				t.Fatalf("got Link %q, want %s", link, tt.link)
+			}
+		})
+	}
+
+	// Only the paths the first clients used have aliases, newer routes live under /api/v1 alone.
+	for _, path := range []string{"/tokens", "/sessions", "/getworkouts", "/exports"} {
+		if rec := alice.do(h, http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: got %d %s, want no alias", path, rec.Code, rec.Body)
+		}
+	}
+
+	rec = alice.do(h, http.MethodGet, "/api/v1/routines", "")
+	var routines []domain.Routine
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &routines) != nil || len(routines) != 1 {
+		t.Fatalf("v1 routines: got %d %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Deprecation") != "" || rec.Header().Get("Link") != "" {
+		t.Fatalf("v1 route is deprecated: %v", rec.Header())
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"gymlog/domain"
+	"net/http"
+	"time"
+)
+
+// handleGetSessions lists the active sessions of the user.
+func (s *gymlogServer) handleGetSessions(w http.ResponseWriter, r *http.Request) {
+	session, _ := sessionFromContext(r.Context())
+
+	sessions, err := s.userRepository.Sessions(session.UserID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, userSession := range sessions {
+		response = append(response, newSessionResponse(userSession, session.ID))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// handleDeleteOtherSessions logs out every session of the user except the current one.
+func (s *gymlogServer) handleDeleteOtherSessions(w http.ResponseWriter, r *http.Request) {
+	session, _ := sessionFromContext(r.Context())
+
+	if err := s.userRepository.DeleteOtherSessions(session.UserID, session.ID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeleteSession logs out one of the sessions of the user.
+func (s *gymlogServer) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := idFromPath(r, "session")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	session, _ := sessionFromContext(r.Context())
+	err = s.userRepository.DeleteSession(session.UserID, sessionID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionResponse is a session as listed to its user, without its tokens.
+type sessionResponse struct {
+	ID         int       `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+func newSessionResponse(session domain.UserSession, currentSessionID int) sessionResponse {
+	return sessionResponse{
+		ID:         session.ID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		Current:    session.ID == currentSessionID,
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func (u testUser) sessions(t *testing.T, h http.Handler) []sessionResponse {
+	t.Helper()
+
+	rec := u.do(h, http.MethodGet, "/api/v1/sessions", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get sessions: got %d %s", rec.Code, rec.Body)
+	}
+	var sessions []sessionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&sessions); err != nil {
+		t.Fatal(err)
+	}
+	return sessions
+}
+
+func TestListSessions(t *testing.T) {
+	h := newTestHandler(t)
+	phone := registerAndLogin(t, h, "alice")
+	rec := serveForm(h, "/api/v1/login", url.Values{"username": {"alice"}, "password": {testPassword}, "device_name": {"work laptop"}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login: got %d %s", rec.Code, rec.Body)
+	}
+
+	sessions := phone.sessions(t, h)
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	devices := map[string]bool{}
+	for _, session := range sessions {
+		devices[session.DeviceName] = session.Current
+		if session.IP == "" || session.CreatedAt.IsZero() {
+			t.Fatalf("session without device details: %+v", session)
+		}
+	}
+	if current, listed := devices["work laptop"]; !listed || current {
+		t.Fatalf("got devices %v, want the work laptop as a non current session", devices)
+	}
+
+	var raw []map[string]any
+	if err := json.NewDecoder(phone.do(h, http.MethodGet, "/api/v1/sessions", "").Body).Decode(&raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"SessionToken", "CSRFToken"} {
+		if _, leaked := raw[0][field]; leaked {
+			t.Fatalf("sessions list exposes %s", field)
+		}
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	h := newTestHandler(t)
+	phone := registerAndLogin(t, h, "alice")
+	laptop := login(t, h, "alice")
+	tablet := login(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+
+	var laptopID int
+	for _, session := range laptop.sessions(t, h) {
+		if session.Current {
+			laptopID = session.ID
+		}
+	}
+	laptopPath := fmt.Sprintf("/api/v1/sessions/%d", laptopID)
+
+	if rec := bob.do(h, http.MethodDelete, laptopPath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("bob revokes alice's session: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := phone.do(h, http.MethodDelete, laptopPath, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke laptop: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := laptop.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked laptop: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := phone.do(h, http.MethodDelete, laptopPath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("revoke twice: got %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := phone.do(h, http.MethodDelete, "/api/v1/sessions", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke other sessions: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := tablet.do(h, http.MethodGet, "/api/v1/routines", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("tablet after revoking other sessions: got %d %s", rec.Code, rec.Body)
+	}
+	if sessions := phone.sessions(t, h); len(sessions) != 1 || !sessions[0].Current {
+		t.Fatalf("got sessions %+v, want only the current one", sessions)
+	}
+	if sessions := bob.sessions(t, h); len(sessions) != 1 {
+		t.Fatalf("bob lost his session: %+v", sessions)
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gymlog/domain"
+	"net/http"
+	"time"
+)
+
+// handleCreateAccessToken creates a personal access token for the user. The token is only in
+// this response, afterwards it is listed without it.
+func (s *gymlogServer) handleCreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	var tokenRequest postAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&tokenRequest); err != nil {
+		writeError(w, r, errInvalidBody)
+		return
+	}
+	token, err := domain.NewAccessToken(tokenRequest.Name, domain.TokenScope(tokenRequest.Scope), tokenRequest.ExpiresAt, time.Now())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	token, secret, err := s.userRepository.CreateAccessToken(user.ID, token)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := newAccessTokenResponse(token)
+	response.Token = secret
+	writeCreated(w, r, fmt.Sprintf(apiV1+"/tokens/%d", token.ID), response)
+}
+
+// handleGetAccessTokens lists the access tokens of the user, without the tokens themselves.
+func (s *gymlogServer) handleGetAccessTokens(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+	tokens, err := s.userRepository.AccessTokens(user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response := make([]accessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		response = append(response, newAccessTokenResponse(token))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// handleGetAccessToken shows an access token of the user, without the token itself.
+func (s *gymlogServer) handleGetAccessToken(w http.ResponseWriter, r *http.Request) {
+	tokenID, err := idFromPath(r, "token")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	token, err := s.userRepository.AccessTokenByID(user.ID, tokenID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, newAccessTokenResponse(token))
+}
+
+// handleDeleteAccessToken revokes an access token of the user.
+func (s *gymlogServer) handleDeleteAccessToken(w http.ResponseWriter, r *http.Request) {
+	tokenID, err := idFromPath(r, "token")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	err = s.userRepository.DeleteAccessToken(user.ID, tokenID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// postAccessTokenRequest creates a token that never expires when ExpiresAt is missing.
+type postAccessTokenRequest struct {
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// accessTokenResponse is an access token as listed to its user, Token is only set when it is created.
+type accessTokenResponse struct {
+	ID         int               `json:"id"`
+	Name       string            `json:"name"`
+	Scope      domain.TokenScope `json:"scope"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ExpiresAt  *time.Time        `json:"expires_at"`
+	LastUsedAt *time.Time        `json:"last_used_at"`
+	Token      string            `json:"token,omitempty"`
+}
+
+func newAccessTokenResponse(token domain.AccessToken) accessTokenResponse {
+	return accessTokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scope:      token.Scope,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
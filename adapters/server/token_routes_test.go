@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createAccessToken creates an access token for the user and returns the response with the token.
+func (u testUser) createAccessToken(t *testing.T, h http.Handler, body string) accessTokenResponse {
+	t.Helper()
+
+	rec := u.do(h, http.MethodPost, "/api/v1/tokens", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create access token: got %d %s", rec.Code, rec.Body)
+	}
+	var token accessTokenResponse
+	if err := json.NewDecoder(rec.Body).Decode(&token); err != nil {
+		t.Fatal(err)
+	}
+	if location := rec.Header().Get("Location"); location != fmt.Sprintf("/api/v1/tokens/%d", token.ID) {
+		t.Fatalf("got Location %q", location)
+	}
+	return token
+}
+
+// doWithToken sends a JSON request authenticated only with the bearer token, like a script would.
+func doWithToken(h http.Handler, token, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAccessTokenScopes(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	alice.createRoutine(t, h, "push day")
+
+	read := alice.createAccessToken(t, h, `{"name":"stats script","scope":"read"}`)
+	write := alice.createAccessToken(t, h, `{"name":"iphone","scope":"write"}`)
+	if !strings.HasPrefix(read.Token, "gla_") || read.Scope != "read" || read.ExpiresAt != nil {
+		t.Fatalf("got access token %+v", read)
+	}
+
+	createRoutine := `{"name":"leg day","exercises":[{"id":1}]}`
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"read token reads", read.Token, http.MethodGet, "/api/v1/routines", "", http.StatusOK},
+		{"read token writes", read.Token, http.MethodPost, "/api/v1/routines", createRoutine, http.StatusForbidden},
+		{"write token writes", write.Token, http.MethodPost, "/api/v1/routines", createRoutine, http.StatusCreated},
+		{"tokens cannot create tokens", write.Token, http.MethodPost, "/api/v1/tokens", `{"name":"more","scope":"write"}`, http.StatusForbidden},
+		{"tokens cannot list sessions", write.Token, http.MethodGet, "/api/v1/sessions", "", http.StatusForbidden},
+		{"unknown token", "gla_nope", http.MethodGet, "/api/v1/routines", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := doWithToken(h, tt.token, tt.method, tt.path, tt.body); rec.Code != tt.want {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+
+	if routines := alice.routines(t, h); len(routines) != 2 {
+		t.Fatalf("got %d routines, want 2", len(routines))
+	}
+}
+
+func TestCreateAccessTokenValidation(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no name", `{"scope":"read"}`},
+		{"unknown scope", `{"name":"x","scope":"admin"}`},
+		{"expired", fmt.Sprintf(`{"name":"x","scope":"read","expires_at":%q}`, past)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := alice.do(h, http.MethodPost, "/api/v1/tokens", tt.body); rec.Code != http.StatusBadRequest {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestListAndRevokeAccessTokens(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	token := alice.createAccessToken(t, h, fmt.Sprintf(`{"name":"cron","scope":"read","expires_at":%q}`, expiresAt.Format(time.RFC3339)))
+	if rec := doWithToken(h, token.Token, http.MethodGet, "/api/v1/workouts", ""); rec.Code != http.StatusOK {
+		t.Fatalf("use token: got %d %s", rec.Code, rec.Body)
+	}
+
+	rec := alice.do(h, http.MethodGet, "/api/v1/tokens", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list tokens: got %d %s", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), token.Token) {
+		t.Fatal("listed tokens include the secret")
+	}
+	var tokens []accessTokenResponse
+	if err := json.NewDecoder(rec.Body).Decode(&tokens); err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 1 || tokens[0].Name != "cron" || tokens[0].LastUsedAt == nil ||
+		tokens[0].ExpiresAt == nil || !tokens[0].ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("got tokens %+v", tokens)
+	}
+
+	tokenPath := fmt.Sprintf("/api/v1/tokens/%d", token.ID)
+	rec = alice.do(h, http.MethodGet, tokenPath, "")
+	var shown accessTokenResponse
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &shown) != nil {
+		t.Fatalf("get token: got %d %s", rec.Code, rec.Body)
+	}
+	if shown.ID != token.ID || shown.Name != "cron" || shown.Token != "" {
+		t.Fatalf("got token %+v", shown)
+	}
+	if rec := bob.do(h, http.MethodGet, tokenPath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("bob gets alice's token: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := bob.do(h, http.MethodDelete, tokenPath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("bob revokes alice's token: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodDelete, tokenPath, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke token: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := doWithToken(h, token.Token, http.MethodGet, "/api/v1/workouts", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked token: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := alice.do(h, http.MethodGet, tokenPath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("get revoked token: got %d %s", rec.Code, rec.Body)
+	}
+}
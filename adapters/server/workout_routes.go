@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gymlog/adapters/application"
+	"gymlog/domain"
+	"io"
+	"net/http"
+	"time"
+)
+
+// handleStartWorkout starts a new workout for a user, optionally from one of their routines.
+func (s *gymlogServer) handleStartWorkout(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+
+	// The body is optional: an empty one starts a free workout.
+	var workoutRequest postWorkoutRequest
+	err := json.NewDecoder(r.Body).Decode(&workoutRequest)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, errInvalidBody)
+		return
+	}
+
+	workout, err := s.routineRepository.StartWorkout(user.ID, workoutRequest.RoutineID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeCreated(w, r, fmt.Sprintf(apiV1+"/workouts/%d", workout.ID), newWorkoutResponse(workout))
+}
+
+// handleGetWorkouts handles the GET request for the workout history of a user.
+func (s *gymlogServer) handleGetWorkouts(w http.ResponseWriter, r *http.Request) {
+	user, _ := userFromContext(r.Context())
+
+	workouts, err := s.routineRepository.GetWorkouts(user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]workoutResponse, 0, len(workouts))
+	for _, workout := range workouts {
+		response = append(response, newWorkoutResponse(workout))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// handleGetWorkout returns a workout of the user with its sets.
+func (s *gymlogServer) handleGetWorkout(w http.ResponseWriter, r *http.Request) {
+	workoutID, err := idFromPath(r, "workout")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	workout, err := s.routineRepository.GetWorkout(user.ID, workoutID)
+	writeWorkout(w, r, workout, err)
+}
+
+// handleAddWorkoutSet logs a set in a workout of the user that is not finished yet.
+func (s *gymlogServer) handleAddWorkoutSet(w http.ResponseWriter, r *http.Request) {
+	workoutID, err := idFromPath(r, "workout")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var setRequest postWorkoutSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&setRequest); err != nil {
+		writeError(w, r, errInvalidBody)
+		return
+	}
+	set, err := domain.NewWorkoutSet(setRequest.ExerciseID, setRequest.SetIndex, setRequest.Reps, setRequest.Weight, setRequest.Completed)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	workout, err := s.routineRepository.AddWorkoutSet(user.ID, workoutID, set)
+	writeWorkout(w, r, workout, err)
+}
+
+// handleFinishWorkout finishes a workout of the user, after that no more sets can be logged.
+func (s *gymlogServer) handleFinishWorkout(w http.ResponseWriter, r *http.Request) {
+	workoutID, err := idFromPath(r, "workout")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, _ := userFromContext(r.Context())
+	workout, err := s.routineRepository.FinishWorkout(user.ID, workoutID)
+	writeWorkout(w, r, workout, err)
+}
+
+// writeWorkout writes the workout returned by a workout operation, or its error.
+func writeWorkout(w http.ResponseWriter, r *http.Request, workout domain.Workout, err error) {
+	if errors.Is(err, application.ErrExerciseNotFound) {
+		// The exercise comes in the body of the request, it is not the resource of the URL
+		err = invalidField("exercise_id", err.Error())
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, newWorkoutResponse(workout))
+}
+
+type postWorkoutRequest struct {
+	RoutineID *int `json:"routine_id"`
+}
+
+type postWorkoutSetRequest struct {
+	ExerciseID int     `json:"exercise_id"`
+	SetIndex   int     `json:"set_index"`
+	Reps       int     `json:"reps"`
+	Weight     float64 `json:"weight"`
+	Completed  bool    `json:"completed"`
+}
+
+// workoutResponse is a workout with its sets as shown to its user.
+type workoutResponse struct {
+	ID         int                  `json:"id"`
+	RoutineID  *int                 `json:"routine_id"`
+	StartedAt  time.Time            `json:"started_at"`
+	FinishedAt *time.Time           `json:"finished_at"`
+	Sets       []workoutSetResponse `json:"sets"`
+}
+
+type workoutSetResponse struct {
+	ExerciseID int     `json:"exercise_id"`
+	SetIndex   int     `json:"set_index"`
+	Reps       int     `json:"reps"`
+	Weight     float64 `json:"weight"`
+	Completed  bool    `json:"completed"`
+}
+
+func newWorkoutResponse(workout domain.Workout) workoutResponse {
+	sets := make([]workoutSetResponse, 0, len(workout.Sets))
+	for _, set := range workout.Sets {
+		sets = append(sets, workoutSetResponse{
+			ExerciseID: set.ExerciseID,
+			SetIndex:   set.SetIndex,
+			Reps:       set.Reps,
+			Weight:     set.Weight,
+			Completed:  set.Completed,
+		})
+	}
+	return workoutResponse{
+		ID:         workout.ID,
+		RoutineID:  workout.RoutineID,
+		StartedAt:  workout.StartedAt,
+		FinishedAt: workout.FinishedAt,
+		Sets:       sets,
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func decodeWorkout(t *testing.T, body []byte) workoutResponse {
+	t.Helper()
+
+	var workout workoutResponse
+	if err := json.Unmarshal(body, &workout); err != nil {
+		t.Fatal(err)
+	}
+	return workout
+}
+
+func TestLogWorkout(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	routineID := alice.createRoutine(t, h, "push day")
+
+	rec := alice.do(h, http.MethodPost, "/api/v1/workouts", fmt.Sprintf(`{"routine_id":%d}`, routineID))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("start workout: got %d %s", rec.Code, rec.Body)
+	}
+	workout := decodeWorkout(t, rec.Body.Bytes())
+	if workout.RoutineID == nil || *workout.RoutineID != routineID || workout.FinishedAt != nil {
+		t.Fatalf("got %+v", workout)
+	}
+	workoutPath := fmt.Sprintf("/api/v1/workouts/%d", workout.ID)
+	if location := rec.Header().Get("Location"); location != workoutPath {
+		t.Fatalf("started workout at %q, want %s", location, workoutPath)
+	}
+
+	sets := []string{
+		`{"exercise_id":1,"set_index":1,"reps":8,"weight":60}`,
+		`{"exercise_id":1,"set_index":2,"reps":6,"weight":60}`,
+		`{"exercise_id":1,"set_index":1,"reps":10,"weight":60,"completed":true}`,
+	}
+	for _, set := range sets {
+		if rec := alice.do(h, http.MethodPost, workoutPath+"/sets", set); rec.Code != http.StatusOK {
+			t.Fatalf("add set: got %d %s", rec.Code, rec.Body)
+		}
+	}
+
+	rec = alice.do(h, http.MethodPost, workoutPath+"/finish", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("finish: got %d %s", rec.Code, rec.Body)
+	}
+	workout = decodeWorkout(t, rec.Body.Bytes())
+	if workout.FinishedAt == nil || len(workout.Sets) != 2 || workout.Sets[0].Reps != 10 || !workout.Sets[0].Completed {
+		t.Fatalf("got %+v", workout)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"add set after finishing", workoutPath + "/sets", `{"exercise_id":1,"set_index":3,"reps":5}`, http.StatusConflict},
+		{"finish twice", workoutPath + "/finish", "", http.StatusConflict},
+		{"invalid body", "/api/v1/workouts", `{"routine_id":"push"}`, http.StatusBadRequest},
+		{"unknown routine", "/api/v1/workouts", `{"routine_id":999}`, http.StatusNotFound},
+		{"unknown action", workoutPath + "/pause", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := alice.do(h, http.MethodPost, tt.path, tt.body); rec.Code != tt.want {
+				t.Fatalf("got %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkoutHistory(t *testing.T) {
+	h := newTestHandler(t)
+	alice := registerAndLogin(t, h, "alice")
+	bob := registerAndLogin(t, h, "bob")
+
+	var ids []int
+	for range 2 {
+		rec := alice.do(h, http.MethodPost, "/api/v1/workouts", "")
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("start workout: got %d %s", rec.Code, rec.Body)
+		}
+		ids = append(ids, decodeWorkout(t, rec.Body.Bytes()).ID)
+	}
+
+	rec := alice.do(h, http.MethodGet, "/api/v1/workouts", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get workouts: got %d %s", rec.Code, rec.Body)
+	}
+	var workouts []workoutResponse
+	if err := json.NewDecoder(rec.Body).Decode(&workouts); err != nil {
+		t.Fatal(err)
+	}
+	if len(workouts) != 2 || workouts[0].ID != ids[1] || workouts[1].ID != ids[0] {
+		t.Fatalf("got %+v, want the most recent first", workouts)
+	}
+
+	workoutPath := fmt.Sprintf("/api/v1/workouts/%d", ids[0])
+	if rec := bob.do(h, http.MethodGet, workoutPath, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("bob gets alice's workout: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := bob.do(h, http.MethodPost, workoutPath+"/finish", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("bob finishes alice's workout: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := bob.do(h, http.MethodGet, "/api/v1/workouts", ""); rec.Code != http.StatusOK || rec.Body.String() != "[]\n" {
+		t.Fatalf("bob's workouts: got %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"gymlog/domain"
+)
+
+// exerciseCatalogJSON is the global exercise catalog shipped with the binary.
+// Bump its version whenever the exercises change so existing databases pick up the new catalog.
+//
+//go:embed exercises_catalog.json
+var exerciseCatalogJSON []byte
+
+// exerciseCatalog is a versioned list of global exercises identified by a stable slug.
+type exerciseCatalog struct {
+	Version   int               `json:"version"`
+	Exercises []catalogExercise `json:"exercises"`
+}
+
+// catalogExercise is an exercise of the catalog. ID is only used the first time the exercise is
+// inserted, so databases seeded before the catalog had slugs keep the IDs their routines reference.
+type catalogExercise struct {
+	ID               int      `json:"id,omitempty"`
+	Slug             string   `json:"slug"`
+	Name             string   `json:"name"`
+	Target           string   `json:"target"`
+	Equipment        string   `json:"equipment,omitempty"`
+	Mechanics        string   `json:"mechanics,omitempty"`
+	MovementPattern  string   `json:"movement_pattern,omitempty"`
+	Unilateral       bool     `json:"unilateral,omitempty"`
+	PrimaryMuscles   []string `json:"primary_muscles,omitempty"`
+	SecondaryMuscles []string `json:"secondary_muscles,omitempty"`
+	Instructions     string   `json:"instructions,omitempty"`
+}
+
+// parseExerciseCatalog decodes and validates a catalog, normalizing every exercise like user ones.
+func parseExerciseCatalog(data []byte) (exerciseCatalog, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var catalog exerciseCatalog
+	if err := decoder.Decode(&catalog); err != nil {
+		return exerciseCatalog{}, fmt.Errorf("exercise catalog: %w", err)
+	}
+	if catalog.Version <= 0 {
+		return exerciseCatalog{}, fmt.Errorf("exercise catalog: invalid version %d", catalog.Version)
+	}
+
+	slugs := make(map[string]bool, len(catalog.Exercises))
+	ids := make(map[int]bool, len(catalog.Exercises))
+	for i, entry := range catalog.Exercises {
+		if entry.Slug == "" {
+			return exerciseCatalog{}, fmt.Errorf("exercise catalog: exercise %d has no slug", i)
+		}
+		if slugs[entry.Slug] {
+			return exerciseCatalog{}, fmt.Errorf("exercise catalog: slug %q is repeated", entry.Slug)
+		}
+		slugs[entry.Slug] = true
+		if entry.ID != 0 {
+			if ids[entry.ID] {
+				return exerciseCatalog{}, fmt.Errorf("exercise catalog: id %d is repeated", entry.ID)
+			}
+			ids[entry.ID] = true
+		}
+
+		exercise, err := domain.CreateExercise(entry.toDomain())
+		if err != nil {
+			return exerciseCatalog{}, fmt.Errorf("exercise catalog: %s: %w", entry.Slug, err)
+		}
+		catalog.Exercises[i] = catalogExerciseFromDomain(entry.ID, exercise)
+	}
+	return catalog, nil
+}
+
+func (e catalogExercise) toDomain() domain.Exercise {
+	return domain.Exercise{
+		Slug:             e.Slug,
+		Name:             e.Name,
+		Target:           e.Target,
+		Equipment:        e.Equipment,
+		Mechanics:        e.Mechanics,
+		MovementPattern:  e.MovementPattern,
+		Unilateral:       e.Unilateral,
+		PrimaryMuscles:   e.PrimaryMuscles,
+		SecondaryMuscles: e.SecondaryMuscles,
+		Instructions:     e.Instructions,
+	}
+}
+
+func catalogExerciseFromDomain(id int, exercise domain.Exercise) catalogExercise {
+	return catalogExercise{
+		ID:               id,
+		Slug:             exercise.Slug,
+		Name:             exercise.Name,
+		Target:           exercise.Target,
+		Equipment:        exercise.Equipment,
+		Mechanics:        exercise.Mechanics,
+		MovementPattern:  exercise.MovementPattern,
+		Unilateral:       exercise.Unilateral,
+		PrimaryMuscles:   exercise.PrimaryMuscles,
+		SecondaryMuscles: exercise.SecondaryMuscles,
+		Instructions:     exercise.Instructions,
+	}
+}
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newSchemaDB creates a migrated SQLite database without exercises and returns its path.
+func newSchemaDB(t *testing.T) string {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "gymlog.db")
+	if _, err := MigrateSqlite(dbPath); err != nil {
+		t.Fatal(err)
+	}
+	return dbPath
+}
+
+func withCatalog(t *testing.T, catalog string) {
+	t.Helper()
+
+	previous := exerciseCatalogJSON
+	exerciseCatalogJSON = []byte(catalog)
+	t.Cleanup(func() { exerciseCatalogJSON = previous })
+}
+
+func openSqlite(t *testing.T, dbPath string) *sqliteStorage {
+	t.Helper()
+
+	store, err := NewSqliteStorage(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store.(*sqliteStorage)
+}
+
+func TestParseEmbeddedExerciseCatalog(t *testing.T) {
+	catalog, err := parseExerciseCatalog(exerciseCatalogJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Exercises) == 0 {
+		t.Fatal("embedded catalog is empty")
+	}
+	// A movement pattern always works more than its target.
+	for _, exercise := range catalog.Exercises {
+		if exercise.MovementPattern != "" && exercise.MovementPattern != "core" && len(exercise.SecondaryMuscles) == 0 {
+			t.Errorf("%s: %s exercise without secondary muscles", exercise.Slug, exercise.MovementPattern)
+		}
+	}
+}
+
+func TestParseExerciseCatalogRejectsInvalidCatalogs(t *testing.T) {
+	tests := map[string]string{
+		"no version":     `{"exercises": []}`,
+		"unknown field":  `{"version": 1, "exercises": [{"slug": "a", "name": "a", "target": "abs", "weight": 3}]}`,
+		"missing slug":   `{"version": 1, "exercises": [{"name": "a", "target": "abs"}]}`,
+		"repeated slug":  `{"version": 1, "exercises": [{"slug": "a", "name": "a", "target": "abs"}, {"slug": "a", "name": "b", "target": "abs"}]}`,
+		"repeated id":    `{"version": 1, "exercises": [{"id": 1, "slug": "a", "name": "a", "target": "abs"}, {"id": 1, "slug": "b", "name": "b", "target": "abs"}]}`,
+		"unknown muscle": `{"version": 1, "exercises": [{"slug": "a", "name": "a", "target": "elbows"}]}`,
+	}
+	for name, catalog := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseExerciseCatalog([]byte(catalog)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestSeedExercisesRollsOutNewVersions(t *testing.T) {
+	dbPath := newSchemaDB(t)
+
+	withCatalog(t, `{"version": 1, "exercises": [
+		{"id": 10, "slug": "push-up", "name": "push-up", "target": "pectorals"},
+		{"id": 11, "slug": "squat", "name": "squat", "target": "quads"}
+	]}`)
+	store := openSqlite(t, dbPath)
+	_, err := store.db.Exec("INSERT INTO users (username, email, password_hash) VALUES ('alice', 'alice@gymlog.test', 'x')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.db.Exec("INSERT INTO routines (id, user_id, name, description) VALUES (1, 1, 'legs', '')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.db.Exec("INSERT INTO routine_exercises (routine_id, exercise_id, order_index, sets, reps) VALUES (1, 11, 0, 3, 6)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	// Version 2 renames squat, adds the muscles it works and a new exercise.
+	withCatalog(t, `{"version": 2, "exercises": [
+		{"id": 10, "slug": "push-up", "name": "push-up", "target": "pectorals"},
+		{"id": 11, "slug": "squat", "name": "back squat", "target": "quads", "equipment": "barbell", "secondary_muscles": ["glutes"]},
+		{"slug": "lunge", "name": "lunge", "target": "quads"}
+	]}`)
+	store = openSqlite(t, dbPath)
+
+	squat, err := store.Exercise(0, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if squat.Slug != "squat" || squat.Name != "back squat" || squat.Equipment != "barbell" || len(squat.SecondaryMuscles) != 1 {
+		t.Fatalf("squat was not updated: %+v", squat)
+	}
+	routine, err := store.Routine(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routine.Exercises) != 1 || routine.Exercises[0].ID != 11 {
+		t.Fatalf("routine lost its exercise: %+v", routine)
+	}
+
+	var count int
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM exercises").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Fatalf("got %d exercises, want 3", count)
+	}
+
+	// Reopening with the same version does not touch the catalog.
+	store.Close()
+	store = openSqlite(t, dbPath)
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM exercises").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Fatalf("got %d exercises after reopening, want 3", count)
+	}
+}
+
+func TestSeedExercisesAdoptsLegacyRows(t *testing.T) {
+	dbPath := newSchemaDB(t)
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rows inserted by the old SQL seed had the catalog IDs but no slug.
+	_, err = db.Exec(`INSERT INTO exercises (id, name, target) VALUES (1, '3/4 sit-up', 'abs'), (2, '45° side bend', 'abs')`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := openSqlite(t, dbPath)
+	rows, err := store.db.Query("SELECT id, slug FROM exercises WHERE id IN (1, 2) ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var slugs []string
+	for rows.Next() {
+		var id int
+		var slug sql.NullString
+		if err := rows.Scan(&id, &slug); err != nil {
+			t.Fatal(err)
+		}
+		slugs = append(slugs, slug.String)
+	}
+	if strings.Join(slugs, ",") != "3-4-sit-up,45-side-bend" {
+		t.Fatalf("legacy rows were not adopted, got slugs %v", slugs)
+	}
+
+	catalog, err := parseExerciseCatalog(exerciseCatalogJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM exercises").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != len(catalog.Exercises) {
+		t.Fatalf("got %d exercises, want %d", count, len(catalog.Exercises))
+	}
+}
@@ -0,0 +1,1384 @@
+package storage
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"gymlog/domain"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryStorage is a thread-safe implementation of the Storage interface that keeps everything in
+// memory, for tests and demos. It behaves like sqliteStorage: same ordering and sql.ErrNoRows for
+// missing rows.
+type memoryStorage struct {
+	mu sync.RWMutex
+
+	users          map[int]domain.User
+	sessions       map[int]domain.UserSession
+	accessTokens   map[int]domain.AccessToken
+	passwordResets map[int]domain.PasswordReset
+	verifications  map[int]domain.EmailVerification
+	totps          map[int]domain.TOTP          // by user ID
+	recoveryCodes  map[int][]memoryRecoveryCode // by user ID
+	mfaChallenges  map[int]domain.MFAChallenge
+	loginAttempts  []domain.LoginAttempt // in the order they were saved
+	dataExports    map[int]memoryDataExport
+	exercises      map[int]domain.Exercise
+	routines       map[int]memoryRoutine
+	workouts       map[int]memoryWorkout
+
+	// Last IDs handed out, like SQLite AUTOINCREMENT IDs are never reused.
+	lastUserID, lastSessionID, lastAccessTokenID, lastPasswordResetID, lastVerificationID, lastMFAChallengeID, lastLoginAttemptID, lastDataExportID, lastExerciseID, lastRoutineID, lastWorkoutID int
+}
+
+type memoryRoutine struct {
+	userID     int
+	routine    domain.Routine
+	sharedWith map[int]bool
+}
+
+type memoryRecoveryCode struct {
+	hash string
+	used bool
+}
+
+type memoryDataExport struct {
+	export    domain.DataExport
+	archive   []byte
+	claimedAt time.Time
+}
+
+type memoryWorkout struct {
+	userID  int
+	workout domain.Workout
+}
+
+// NewMemoryStorage creates an empty in-memory storage with the exercise catalog.
+func NewMemoryStorage() (Storage, error) {
+	catalog, err := parseExerciseCatalog(exerciseCatalogJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &memoryStorage{
+		users:          make(map[int]domain.User),
+		sessions:       make(map[int]domain.UserSession),
+		accessTokens:   make(map[int]domain.AccessToken),
+		passwordResets: make(map[int]domain.PasswordReset),
+		verifications:  make(map[int]domain.EmailVerification),
+		totps:          make(map[int]domain.TOTP),
+		recoveryCodes:  make(map[int][]memoryRecoveryCode),
+		mfaChallenges:  make(map[int]domain.MFAChallenge),
+		dataExports:    make(map[int]memoryDataExport),
+		exercises:      make(map[int]domain.Exercise),
+		routines:       make(map[int]memoryRoutine),
+		workouts:       make(map[int]memoryWorkout),
+	}
+	for _, entry := range catalog.Exercises {
+		exercise := storedExercise(entry.toDomain())
+		exercise.ID = entry.ID
+		if exercise.ID == 0 || s.exercises[exercise.ID].ID != 0 {
+			exercise.ID = s.lastExerciseID + 1
+		}
+		s.exercises[exercise.ID] = exercise
+		s.lastExerciseID = max(s.lastExerciseID, exercise.ID)
+	}
+	return s, nil
+}
+
+func (s *memoryStorage) Close() error {
+	return nil
+}
+
+// Exercises returns the global exercises plus the ones created by the user that match the query.
+func (s *memoryStorage) Exercises(userID int, query domain.ExerciseQuery) ([]domain.Exercise, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if query.Sort == "" {
+		query.Sort = domain.SortByName
+	}
+
+	exercises := []domain.Exercise{}
+	for _, exercise := range s.exercises {
+		if isVisibleExercise(exercise, userID) && matchesExerciseQuery(exercise, query) {
+			exercises = append(exercises, copyExercise(exercise))
+		}
+	}
+
+	compare := func(a, b domain.Exercise) int {
+		return compareExerciseCursors(query.CursorAfter(a), query.CursorAfter(b))
+	}
+	if query.Descending() {
+		slices.SortFunc(exercises, func(a, b domain.Exercise) int { return compare(b, a) })
+	} else {
+		slices.SortFunc(exercises, compare)
+	}
+
+	// Keyset pagination: skip the exercises up to the cursor.
+	if query.After != nil {
+		exercises = slices.DeleteFunc(exercises, func(exercise domain.Exercise) bool {
+			c := compareExerciseCursors(query.CursorAfter(exercise), *query.After)
+			return c == 0 || (c < 0) != query.Descending()
+		})
+	}
+	if query.Limit > 0 && len(exercises) > query.Limit {
+		exercises = exercises[:query.Limit]
+	}
+	return exercises, nil
+}
+
+func isVisibleExercise(exercise domain.Exercise, userID int) bool {
+	return exercise.OwnerID == nil || *exercise.OwnerID == userID
+}
+
+func matchesExerciseQuery(exercise domain.Exercise, query domain.ExerciseQuery) bool {
+	filters := []struct {
+		value  string
+		filter string
+	}{
+		{exercise.Target, query.Target},
+		{exercise.Equipment, query.Equipment},
+		{exercise.Mechanics, query.Mechanics},
+		{exercise.MovementPattern, query.MovementPattern},
+	}
+	for _, f := range filters {
+		if f.filter != "" && f.value != f.filter {
+			return false
+		}
+	}
+	if query.Unilateral != nil && exercise.Unilateral != *query.Unilateral {
+		return false
+	}
+	if query.Muscle != "" && !slices.Contains(exercise.PrimaryMuscles, query.Muscle) && !slices.Contains(exercise.SecondaryMuscles, query.Muscle) {
+		return false
+	}
+	// Like the LIKE fallback of SQLite: every term must be part of the name, ignoring case.
+	name := strings.ToLower(exercise.Name)
+	for _, term := range strings.Fields(query.Search) {
+		if !strings.Contains(name, strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareExerciseCursors compares two positions of the same sort in ascending order.
+// Names are compared case-insensitively and the ID always breaks ties.
+func compareExerciseCursors(a, b domain.ExerciseCursor) int {
+	switch a.Sort {
+	case domain.SortByTarget, domain.SortByTargetDesc:
+		if c := strings.Compare(a.Target, b.Target); c != 0 {
+			return c
+		}
+		fallthrough
+	case domain.SortByName, domain.SortByNameDesc:
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// Exercise returns an exercise visible to the user, other exercises are reported as sql.ErrNoRows.
+func (s *memoryStorage) Exercise(userID int, exerciseID int) (domain.Exercise, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exercise, exists := s.exercises[exerciseID]
+	if !exists || !isVisibleExercise(exercise, userID) {
+		return domain.Exercise{}, sql.ErrNoRows
+	}
+	return copyExercise(exercise), nil
+}
+
+func (s *memoryStorage) SaveExercise(userID int, exercise domain.Exercise) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastExerciseID++
+	exercise = storedExercise(exercise)
+	exercise.ID = s.lastExerciseID
+	exercise.Slug = ""
+	exercise.OwnerID = &userID
+	s.exercises[exercise.ID] = exercise
+	return exercise.ID, nil
+}
+
+// UpdateExercise updates an exercise created by the user, global exercises cannot be changed.
+func (s *memoryStorage) UpdateExercise(userID int, exercise domain.Exercise) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.exercises[exercise.ID]
+	if !exists || current.OwnerID == nil || *current.OwnerID != userID {
+		return sql.ErrNoRows
+	}
+	exercise = storedExercise(exercise)
+	exercise.Slug = ""
+	exercise.OwnerID = &userID
+	s.exercises[exercise.ID] = exercise
+	return nil
+}
+
+// DeleteExercise deletes an exercise created by the user as long as no routine or workout uses it.
+func (s *memoryStorage) DeleteExercise(userID int, exerciseID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exercise, exists := s.exercises[exerciseID]
+	if !exists || exercise.OwnerID == nil || *exercise.OwnerID != userID {
+		return sql.ErrNoRows
+	}
+	for _, r := range s.routines {
+		for _, detail := range r.routine.Exercises {
+			if detail.ID == exerciseID {
+				return ErrExerciseInUse
+			}
+		}
+	}
+	for _, w := range s.workouts {
+		for _, set := range w.workout.Sets {
+			if set.ExerciseID == exerciseID {
+				return ErrExerciseInUse
+			}
+		}
+	}
+	delete(s.exercises, exerciseID)
+	return nil
+}
+
+// storedExercise returns a copy of the exercise with the muscles sorted by name, as SQLite loads them.
+func storedExercise(exercise domain.Exercise) domain.Exercise {
+	exercise = copyExercise(exercise)
+	slices.Sort(exercise.PrimaryMuscles)
+	slices.Sort(exercise.SecondaryMuscles)
+	return exercise
+}
+
+// copyAccessToken copies the times the token points to, so callers cannot change the stored token.
+func copyAccessToken(token domain.AccessToken) domain.AccessToken {
+	if token.ExpiresAt != nil {
+		expiresAt := *token.ExpiresAt
+		token.ExpiresAt = &expiresAt
+	}
+	if token.LastUsedAt != nil {
+		lastUsedAt := *token.LastUsedAt
+		token.LastUsedAt = &lastUsedAt
+	}
+	return token
+}
+
+func copyExercise(exercise domain.Exercise) domain.Exercise {
+	exercise.PrimaryMuscles = append([]string{}, exercise.PrimaryMuscles...)
+	exercise.SecondaryMuscles = append([]string{}, exercise.SecondaryMuscles...)
+	if exercise.OwnerID != nil {
+		ownerID := *exercise.OwnerID
+		exercise.OwnerID = &ownerID
+	}
+	return exercise
+}
+
+func (s *memoryStorage) SaveRoutine(userID int, routine domain.Routine) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastRoutineID++
+	routine = copyRoutine(routine)
+	routine.ID = s.lastRoutineID
+	routine.UserID = userID
+	s.routines[routine.ID] = memoryRoutine{userID: userID, routine: routine, sharedWith: make(map[int]bool)}
+	return routine.ID, nil
+}
+
+// UpdateRoutine replaces the name, description and exercises of a routine owned by the user.
+func (s *memoryStorage) UpdateRoutine(userID int, routine domain.Routine) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.routines[routine.ID]
+	if !exists || current.userID != userID {
+		return sql.ErrNoRows
+	}
+	current.routine = copyRoutine(routine)
+	current.routine.UserID = userID
+	s.routines[routine.ID] = current
+	return nil
+}
+
+// DeleteRoutine deletes a routine owned by the user and detaches the workouts started from it.
+func (s *memoryStorage) DeleteRoutine(userID int, routineID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.routines[routineID]
+	if !exists || current.userID != userID {
+		return sql.ErrNoRows
+	}
+	delete(s.routines, routineID)
+	for id, w := range s.workouts {
+		if w.workout.RoutineID != nil && *w.workout.RoutineID == routineID {
+			w.workout.RoutineID = nil
+			s.workouts[id] = w
+		}
+	}
+	return nil
+}
+
+func (s *memoryStorage) Routines(userID int) ([]domain.Routine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	routines := []domain.Routine{}
+	for _, r := range s.routines {
+		if r.userID == userID {
+			routines = append(routines, copyRoutine(r.routine))
+		}
+	}
+	slices.SortFunc(routines, func(a, b domain.Routine) int { return cmp.Compare(a.ID, b.ID) })
+	return routines, nil
+}
+
+// Routine returns a routine owned by or shared with the user, other routines are reported as sql.ErrNoRows.
+func (s *memoryStorage) Routine(userID int, routineID int) (domain.Routine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, exists := s.routines[routineID]
+	if !exists || (r.userID != userID && !r.sharedWith[userID]) {
+		return domain.Routine{}, sql.ErrNoRows
+	}
+	return copyRoutine(r.routine), nil
+}
+
+// ShareRoutine gives another user read access to a routine owned by the user.
+func (s *memoryStorage) ShareRoutine(userID int, routineID int, sharedWithUserID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, exists := s.routines[routineID]
+	if !exists || r.userID != userID {
+		return sql.ErrNoRows
+	}
+	r.sharedWith[sharedWithUserID] = true
+	return nil
+}
+
+func (s *memoryStorage) UnshareRoutine(userID int, routineID int, sharedWithUserID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, exists := s.routines[routineID]
+	if !exists || r.userID != userID || !r.sharedWith[sharedWithUserID] {
+		return sql.ErrNoRows
+	}
+	delete(r.sharedWith, sharedWithUserID)
+	return nil
+}
+
+func copyRoutine(routine domain.Routine) domain.Routine {
+	routine.Exercises = append([]domain.ExerciseDetail{}, routine.Exercises...)
+	return routine
+}
+
+func (s *memoryStorage) Users(username string) ([]domain.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := []domain.User{}
+	for _, user := range s.users {
+		if strings.EqualFold(user.Username, username) {
+			users = append(users, copyUser(user))
+		}
+	}
+	return users, nil
+}
+
+// User returns the user with the given ID, unknown users are reported as sql.ErrNoRows.
+func (s *memoryStorage) User(userID int) (domain.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exists := s.users[userID]
+	if !exists {
+		return domain.User{}, sql.ErrNoRows
+	}
+	return copyUser(user), nil
+}
+
+// UserByEmail returns the user with the given email, unknown emails are reported as sql.ErrNoRows.
+func (s *memoryStorage) UserByEmail(email string) (domain.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if strings.EqualFold(user.Email, email) {
+			return copyUser(user), nil
+		}
+	}
+	return domain.User{}, sql.ErrNoRows
+}
+
+// SaveUser saves a new user, usernames and emails are unique ignoring case like in the users table.
+func (s *memoryStorage) SaveUser(username string, email string, passwordHash string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if strings.EqualFold(user.Username, username) {
+			return 0, ErrUsernameTaken
+		}
+		if strings.EqualFold(user.Email, email) {
+			return 0, ErrEmailTaken
+		}
+	}
+	s.lastUserID++
+	s.users[s.lastUserID] = domain.User{ID: s.lastUserID, Username: username, Email: email, PasswordHash: passwordHash}
+	return s.lastUserID, nil
+}
+
+func copyUser(user domain.User) domain.User {
+	if user.EmailVerifiedAt != nil {
+		verifiedAt := *user.EmailVerifiedAt
+		user.EmailVerifiedAt = &verifiedAt
+	}
+	if user.DeletionScheduledAt != nil {
+		deleteAt := *user.DeletionScheduledAt
+		user.DeletionScheduledAt = &deleteAt
+	}
+	return user
+}
+
+// ChangePassword changes the password of the user, which logs out every other session, revokes
+// their access tokens and invalidates their password resets. Unknown users are reported as sql.ErrNoRows.
+func (s *memoryStorage) ChangePassword(userID int, passwordHash string, keepSessionID int, changedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[userID]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	user.PasswordHash = passwordHash
+	s.users[userID] = user
+	for id, session := range s.sessions {
+		if session.UserID == userID && id != keepSessionID {
+			delete(s.sessions, id)
+		}
+	}
+	for id, token := range s.accessTokens {
+		if token.UserID == userID {
+			delete(s.accessTokens, id)
+		}
+	}
+	for id, reset := range s.passwordResets {
+		if reset.UserID == userID && reset.UsedAt == nil {
+			reset.UsedAt = &changedAt
+			s.passwordResets[id] = reset
+		}
+	}
+	return nil
+}
+
+// ChangeEmail changes the email of the user, which has to be verified again. Emails of other
+// users are reported as ErrEmailTaken and unknown users as sql.ErrNoRows.
+func (s *memoryStorage) ChangeEmail(userID int, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[userID]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	for _, other := range s.users {
+		if other.ID != userID && strings.EqualFold(other.Email, email) {
+			return ErrEmailTaken
+		}
+	}
+	user.Email = email
+	user.EmailVerifiedAt = nil
+	s.users[userID] = user
+	return nil
+}
+
+// ScheduleUserDeletion sets when the user will be deleted by DeleteScheduledUsers, nil cancels
+// the deletion. Unknown users are reported as sql.ErrNoRows.
+func (s *memoryStorage) ScheduleUserDeletion(userID int, deleteAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[userID]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	user.DeletionScheduledAt = nil
+	if deleteAt != nil {
+		at := *deleteAt
+		user.DeletionScheduledAt = &at
+	}
+	s.users[userID] = user
+	return nil
+}
+
+// DeleteScheduledUsers deletes the users scheduled for deletion at the given time, with all their
+// data, and returns how many.
+func (s *memoryStorage) DeleteScheduledUsers(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for userID, user := range s.users {
+		if user.DeletionScheduledAt == nil || user.DeletionScheduledAt.After(now) {
+			continue
+		}
+		s.deleteUser(userID)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// deleteUser deletes the user with everything that references them, like the foreign keys of
+// the SQL storages. The caller holds the lock.
+func (s *memoryStorage) deleteUser(userID int) {
+	delete(s.users, userID)
+	delete(s.totps, userID)
+	delete(s.recoveryCodes, userID)
+	for id, session := range s.sessions {
+		if session.UserID == userID {
+			delete(s.sessions, id)
+		}
+	}
+	for id, token := range s.accessTokens {
+		if token.UserID == userID {
+			delete(s.accessTokens, id)
+		}
+	}
+	for id, reset := range s.passwordResets {
+		if reset.UserID == userID {
+			delete(s.passwordResets, id)
+		}
+	}
+	for id, verification := range s.verifications {
+		if verification.UserID == userID {
+			delete(s.verifications, id)
+		}
+	}
+	for id, challenge := range s.mfaChallenges {
+		if challenge.UserID == userID {
+			delete(s.mfaChallenges, id)
+		}
+	}
+	for id, e := range s.dataExports {
+		if e.export.UserID == userID {
+			delete(s.dataExports, id)
+		}
+	}
+	for id, exercise := range s.exercises {
+		if exercise.OwnerID != nil && *exercise.OwnerID == userID {
+			delete(s.exercises, id)
+		}
+	}
+	for id, w := range s.workouts {
+		if w.userID == userID {
+			delete(s.workouts, id)
+		}
+	}
+	for id, r := range s.routines {
+		if r.userID != userID {
+			delete(r.sharedWith, userID)
+			continue
+		}
+		delete(s.routines, id)
+		for workoutID, w := range s.workouts {
+			if w.workout.RoutineID != nil && *w.workout.RoutineID == id {
+				w.workout.RoutineID = nil
+				s.workouts[workoutID] = w
+			}
+		}
+	}
+}
+
+// SaveSession saves a new session, tokens are unique like in the sessions table.
+func (s *memoryStorage) SaveSession(session domain.UserSession) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.sessions {
+		if existing.SessionToken == session.SessionToken {
+			return 0, errors.New("session token is already in use")
+		}
+	}
+	s.lastSessionID++
+	session.ID = s.lastSessionID
+	s.sessions[session.ID] = session
+	return session.ID, nil
+}
+
+// Session returns the session with the given token, unknown tokens are reported as sql.ErrNoRows.
+func (s *memoryStorage) Session(sessionToken string) (domain.UserSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, session := range s.sessions {
+		if session.SessionToken == sessionToken {
+			return session, nil
+		}
+	}
+	return domain.UserSession{}, sql.ErrNoRows
+}
+
+// Sessions returns the sessions of the user, the most recently used first.
+func (s *memoryStorage) Sessions(userID int) ([]domain.UserSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessions := []domain.UserSession{}
+	for _, session := range s.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b domain.UserSession) int {
+		if c := b.LastSeenAt.Compare(a.LastSeenAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return sessions, nil
+}
+
+func (s *memoryStorage) TouchSession(sessionID int, lastSeenAt, expiresAt time.Time, ip string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, exists := s.sessions[sessionID]
+	if !exists {
+		return nil
+	}
+	session.LastSeenAt = lastSeenAt
+	session.ExpiresAt = expiresAt
+	session.IP = ip
+	s.sessions[sessionID] = session
+	return nil
+}
+
+// DeleteSession deletes a session of the user, other sessions are reported as sql.ErrNoRows.
+func (s *memoryStorage) DeleteSession(userID int, sessionID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, exists := s.sessions[sessionID]
+	if !exists || session.UserID != userID {
+		return sql.ErrNoRows
+	}
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+// DeleteOtherSessions deletes every session of the user except the given one.
+func (s *memoryStorage) DeleteOtherSessions(userID int, sessionID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, session := range s.sessions {
+		if session.UserID == userID && id != sessionID {
+			delete(s.sessions, id)
+		}
+	}
+	return nil
+}
+
+// DeleteExpiredSessions deletes the sessions that expired at the given time and returns how many.
+func (s *memoryStorage) DeleteExpiredSessions(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for id, session := range s.sessions {
+		if session.IsExpired(now) {
+			delete(s.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// SaveLoginAttempt records a login attempt in the audit log.
+func (s *memoryStorage) SaveLoginAttempt(attempt domain.LoginAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastLoginAttemptID++
+	attempt.ID = s.lastLoginAttemptID
+	s.loginAttempts = append(s.loginAttempts, attempt)
+	return nil
+}
+
+// LoginAttempts returns the last succeeded and failed logins of a username since the given
+// time, the newest first and at most limit of them.
+func (s *memoryStorage) LoginAttempts(username string, since time.Time, limit int) ([]domain.LoginAttempt, error) {
+	return s.lastLoginAttempts(since, limit, func(attempt domain.LoginAttempt) bool {
+		return attempt.Username == username && attempt.Outcome != domain.LoginLocked
+	}), nil
+}
+
+// LoginFailuresByIP returns the last failed logins from an IP since the given time, the newest
+// first and at most limit of them.
+func (s *memoryStorage) LoginFailuresByIP(ip string, since time.Time, limit int) ([]domain.LoginAttempt, error) {
+	return s.lastLoginAttempts(since, limit, func(attempt domain.LoginAttempt) bool {
+		return attempt.IP == ip && attempt.Outcome == domain.LoginFailed
+	}), nil
+}
+
+func (s *memoryStorage) lastLoginAttempts(since time.Time, limit int, match func(domain.LoginAttempt) bool) []domain.LoginAttempt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	attempts := []domain.LoginAttempt{}
+	for _, attempt := range s.loginAttempts {
+		if match(attempt) && !attempt.CreatedAt.Before(since) {
+			attempts = append(attempts, attempt)
+		}
+	}
+	slices.SortStableFunc(attempts, func(a, b domain.LoginAttempt) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return attempts[:min(limit, len(attempts))]
+}
+
+// DeleteLoginAttempts deletes the login attempts older than the given time and returns how many were deleted.
+func (s *memoryStorage) DeleteLoginAttempts(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.loginAttempts[:0]
+	for _, attempt := range s.loginAttempts {
+		if !attempt.CreatedAt.Before(before) {
+			kept = append(kept, attempt)
+		}
+	}
+	deleted := len(s.loginAttempts) - len(kept)
+	s.loginAttempts = kept
+	return deleted, nil
+}
+
+// SaveAccessToken saves a new access token, hashes are unique like in the access_tokens table.
+func (s *memoryStorage) SaveAccessToken(token domain.AccessToken) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.accessTokens {
+		if existing.TokenHash == token.TokenHash {
+			return 0, errors.New("access token is already in use")
+		}
+	}
+	s.lastAccessTokenID++
+	token.ID = s.lastAccessTokenID
+	s.accessTokens[token.ID] = copyAccessToken(token)
+	return token.ID, nil
+}
+
+// AccessToken returns the access token with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *memoryStorage) AccessToken(tokenHash string) (domain.AccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, token := range s.accessTokens {
+		if token.TokenHash == tokenHash {
+			return copyAccessToken(token), nil
+		}
+	}
+	return domain.AccessToken{}, sql.ErrNoRows
+}
+
+// AccessTokens returns the access tokens of the user, the newest first.
+func (s *memoryStorage) AccessTokens(userID int) ([]domain.AccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens := []domain.AccessToken{}
+	for _, token := range s.accessTokens {
+		if token.UserID == userID {
+			tokens = append(tokens, copyAccessToken(token))
+		}
+	}
+	slices.SortFunc(tokens, func(a, b domain.AccessToken) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return tokens, nil
+}
+
+func (s *memoryStorage) TouchAccessToken(tokenID int, lastUsedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, exists := s.accessTokens[tokenID]
+	if !exists {
+		return nil
+	}
+	token.LastUsedAt = &lastUsedAt
+	s.accessTokens[tokenID] = token
+	return nil
+}
+
+// DeleteAccessToken deletes an access token of the user, other tokens are reported as sql.ErrNoRows.
+func (s *memoryStorage) DeleteAccessToken(userID int, tokenID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, exists := s.accessTokens[tokenID]
+	if !exists || token.UserID != userID {
+		return sql.ErrNoRows
+	}
+	delete(s.accessTokens, tokenID)
+	return nil
+}
+
+// SavePasswordReset saves a new password reset, hashes are unique like in the password_resets table.
+func (s *memoryStorage) SavePasswordReset(reset domain.PasswordReset) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.passwordResets {
+		if existing.TokenHash == reset.TokenHash {
+			return 0, errors.New("password reset token is already in use")
+		}
+	}
+	s.lastPasswordResetID++
+	reset.ID = s.lastPasswordResetID
+	reset.UsedAt = nil
+	s.passwordResets[reset.ID] = reset
+	return reset.ID, nil
+}
+
+// PasswordReset returns the password reset with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *memoryStorage) PasswordReset(tokenHash string) (domain.PasswordReset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, reset := range s.passwordResets {
+		if reset.TokenHash == tokenHash {
+			return copyPasswordReset(reset), nil
+		}
+	}
+	return domain.PasswordReset{}, sql.ErrNoRows
+}
+
+// PasswordResets returns the password resets of the user created since the given time, the newest first.
+func (s *memoryStorage) PasswordResets(userID int, since time.Time) ([]domain.PasswordReset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resets := []domain.PasswordReset{}
+	for _, reset := range s.passwordResets {
+		if reset.UserID == userID && !reset.CreatedAt.Before(since) {
+			resets = append(resets, copyPasswordReset(reset))
+		}
+	}
+	slices.SortFunc(resets, func(a, b domain.PasswordReset) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return resets, nil
+}
+
+func copyPasswordReset(reset domain.PasswordReset) domain.PasswordReset {
+	if reset.UsedAt != nil {
+		usedAt := *reset.UsedAt
+		reset.UsedAt = &usedAt
+	}
+	return reset
+}
+
+// ResetPassword uses a password reset to change the password of its user, which also logs them
+// out everywhere, revokes their access tokens and invalidates their other resets. Used resets are
+// reported as sql.ErrNoRows.
+func (s *memoryStorage) ResetPassword(resetID int, passwordHash string, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reset, exists := s.passwordResets[resetID]
+	if !exists || reset.UsedAt != nil {
+		return sql.ErrNoRows
+	}
+	user := s.users[reset.UserID]
+	user.PasswordHash = passwordHash
+	s.users[reset.UserID] = user
+	for id, session := range s.sessions {
+		if session.UserID == reset.UserID {
+			delete(s.sessions, id)
+		}
+	}
+	for id, token := range s.accessTokens {
+		if token.UserID == reset.UserID {
+			delete(s.accessTokens, id)
+		}
+	}
+	for id, other := range s.passwordResets {
+		if other.UserID == reset.UserID && other.UsedAt == nil {
+			other.UsedAt = &usedAt
+			s.passwordResets[id] = other
+		}
+	}
+	return nil
+}
+
+// SaveEmailVerification saves a new email verification, hashes are unique like in the email_verifications table.
+func (s *memoryStorage) SaveEmailVerification(verification domain.EmailVerification) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.verifications {
+		if existing.TokenHash == verification.TokenHash {
+			return 0, errors.New("email verification token is already in use")
+		}
+	}
+	s.lastVerificationID++
+	verification.ID = s.lastVerificationID
+	verification.UsedAt = nil
+	s.verifications[verification.ID] = verification
+	return verification.ID, nil
+}
+
+// EmailVerification returns the email verification with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *memoryStorage) EmailVerification(tokenHash string) (domain.EmailVerification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, verification := range s.verifications {
+		if verification.TokenHash == tokenHash {
+			return copyEmailVerification(verification), nil
+		}
+	}
+	return domain.EmailVerification{}, sql.ErrNoRows
+}
+
+// EmailVerifications returns the email verifications sent to the user since the given time, the newest first.
+func (s *memoryStorage) EmailVerifications(userID int, since time.Time) ([]domain.EmailVerification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	verifications := []domain.EmailVerification{}
+	for _, verification := range s.verifications {
+		if verification.UserID == userID && !verification.CreatedAt.Before(since) {
+			verifications = append(verifications, copyEmailVerification(verification))
+		}
+	}
+	slices.SortFunc(verifications, func(a, b domain.EmailVerification) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return verifications, nil
+}
+
+// VerifyEmail uses an email verification to mark the email of its user as verified and
+// invalidates their other verifications. Used verifications, and the ones sent to an email the
+// user no longer has, are reported as sql.ErrNoRows.
+func (s *memoryStorage) VerifyEmail(verificationID int, verifiedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	verification, exists := s.verifications[verificationID]
+	if !exists || verification.UsedAt != nil {
+		return sql.ErrNoRows
+	}
+	user, exists := s.users[verification.UserID]
+	if !exists || user.Email != verification.Email {
+		return sql.ErrNoRows
+	}
+	user.EmailVerifiedAt = &verifiedAt
+	s.users[user.ID] = user
+	for id, other := range s.verifications {
+		if other.UserID == user.ID && other.UsedAt == nil {
+			other.UsedAt = &verifiedAt
+			s.verifications[id] = other
+		}
+	}
+	return nil
+}
+
+func copyEmailVerification(verification domain.EmailVerification) domain.EmailVerification {
+	if verification.UsedAt != nil {
+		usedAt := *verification.UsedAt
+		verification.UsedAt = &usedAt
+	}
+	return verification
+}
+
+// TOTP returns the authenticator app of the user, users without one are reported as sql.ErrNoRows.
+func (s *memoryStorage) TOTP(userID int) (domain.TOTP, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	totp, exists := s.totps[userID]
+	if !exists {
+		return domain.TOTP{}, sql.ErrNoRows
+	}
+	if totp.ConfirmedAt != nil {
+		confirmedAt := *totp.ConfirmedAt
+		totp.ConfirmedAt = &confirmedAt
+	}
+	return totp, nil
+}
+
+// SaveTOTP starts the enrollment of an authenticator app, replacing an unconfirmed one. A
+// confirmed app is kept and reported as sql.ErrNoRows, it has to be deleted first.
+func (s *memoryStorage) SaveTOTP(totp domain.TOTP) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, exists := s.totps[totp.UserID]; exists && existing.Enabled() {
+		return sql.ErrNoRows
+	}
+	totp.ConfirmedAt = nil
+	totp.LastStep = 0
+	s.totps[totp.UserID] = totp
+	return nil
+}
+
+// ConfirmTOTP enables the authenticator app of the user after its first code, of the given
+// step, and replaces their recovery codes. Apps that are missing or already confirmed are
+// reported as sql.ErrNoRows.
+func (s *memoryStorage) ConfirmTOTP(userID int, confirmedAt time.Time, step int64, recoveryCodeHashes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	totp, exists := s.totps[userID]
+	if !exists || totp.Enabled() {
+		return sql.ErrNoRows
+	}
+	totp.ConfirmedAt = &confirmedAt
+	totp.LastStep = step
+	s.totps[userID] = totp
+
+	codes := make([]memoryRecoveryCode, 0, len(recoveryCodeHashes))
+	for _, hash := range recoveryCodeHashes {
+		codes = append(codes, memoryRecoveryCode{hash: hash})
+	}
+	s.recoveryCodes[userID] = codes
+	return nil
+}
+
+// UseTOTPStep records that a code of the step was used. Steps that are not newer than the last
+// one used are reported as sql.ErrNoRows, so every code only works once.
+func (s *memoryStorage) UseTOTPStep(userID int, step int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	totp, exists := s.totps[userID]
+	if !exists || !totp.Enabled() || totp.LastStep >= step {
+		return sql.ErrNoRows
+	}
+	totp.LastStep = step
+	s.totps[userID] = totp
+	return nil
+}
+
+// UseRecoveryCode marks a recovery code of the user as used, unknown and used codes are
+// reported as sql.ErrNoRows.
+func (s *memoryStorage) UseRecoveryCode(userID int, codeHash string, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, code := range s.recoveryCodes[userID] {
+		if code.hash == codeHash && !code.used {
+			s.recoveryCodes[userID][i].used = true
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// DeleteTOTP disables two-factor authentication for the user, with their recovery codes and
+// pending logins.
+func (s *memoryStorage) DeleteTOTP(userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.totps, userID)
+	delete(s.recoveryCodes, userID)
+	for id, challenge := range s.mfaChallenges {
+		if challenge.UserID == userID {
+			delete(s.mfaChallenges, id)
+		}
+	}
+	return nil
+}
+
+// SaveMFAChallenge saves a new pending login, replacing the one the user had. Hashes are unique
+// like in the mfa_challenges table.
+func (s *memoryStorage) SaveMFAChallenge(challenge domain.MFAChallenge) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.mfaChallenges {
+		if existing.TokenHash == challenge.TokenHash {
+			return 0, errors.New("MFA challenge token is already in use")
+		}
+	}
+	for id, existing := range s.mfaChallenges {
+		if existing.UserID == challenge.UserID {
+			delete(s.mfaChallenges, id)
+		}
+	}
+	s.lastMFAChallengeID++
+	challenge.ID = s.lastMFAChallengeID
+	challenge.Attempts = 0
+	s.mfaChallenges[challenge.ID] = challenge
+	return challenge.ID, nil
+}
+
+// MFAChallenge returns the pending login with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *memoryStorage) MFAChallenge(tokenHash string) (domain.MFAChallenge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, challenge := range s.mfaChallenges {
+		if challenge.TokenHash == tokenHash {
+			return challenge, nil
+		}
+	}
+	return domain.MFAChallenge{}, sql.ErrNoRows
+}
+
+// FailMFAChallenge counts a wrong code typed for a pending login.
+func (s *memoryStorage) FailMFAChallenge(challengeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if challenge, exists := s.mfaChallenges[challengeID]; exists {
+		challenge.Attempts++
+		s.mfaChallenges[challengeID] = challenge
+	}
+	return nil
+}
+
+// DeleteMFAChallenge ends a pending login, unknown challenges are reported as sql.ErrNoRows so
+// that only one request can complete a login.
+func (s *memoryStorage) DeleteMFAChallenge(challengeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.mfaChallenges[challengeID]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(s.mfaChallenges, challengeID)
+	return nil
+}
+
+// SaveDataExport saves a new data export, which waits for FinishDataExport.
+func (s *memoryStorage) SaveDataExport(export domain.DataExport) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastDataExportID++
+	export.ID = s.lastDataExportID
+	export.CompletedAt = nil
+	export.ExpiresAt = nil
+	s.dataExports[export.ID] = memoryDataExport{export: export}
+	return export.ID, nil
+}
+
+// DataExport returns a data export of the user, other exports are reported as sql.ErrNoRows.
+func (s *memoryStorage) DataExport(userID int, exportID int) (domain.DataExport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, exists := s.dataExports[exportID]
+	if !exists || e.export.UserID != userID {
+		return domain.DataExport{}, sql.ErrNoRows
+	}
+	return copyDataExport(e.export), nil
+}
+
+// DataExports returns the data exports of the user, the newest first.
+func (s *memoryStorage) DataExports(userID int) ([]domain.DataExport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exports := []domain.DataExport{}
+	for _, e := range s.dataExports {
+		if e.export.UserID == userID {
+			exports = append(exports, copyDataExport(e.export))
+		}
+	}
+	slices.SortFunc(exports, func(a, b domain.DataExport) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return exports, nil
+}
+
+// ClaimDataExports marks the pending data exports as building and returns them, the oldest first.
+// Exports left building since before staleBefore are claimed again, their builder died.
+func (s *memoryStorage) ClaimDataExports(claimedAt, staleBefore time.Time) ([]domain.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exports := []domain.DataExport{}
+	for id, e := range s.dataExports {
+		stale := e.export.Status == domain.DataExportBuilding && e.claimedAt.Before(staleBefore)
+		if e.export.Status == domain.DataExportPending || stale {
+			e.export.Status = domain.DataExportBuilding
+			e.claimedAt = claimedAt
+			s.dataExports[id] = e
+			exports = append(exports, copyDataExport(e.export))
+		}
+	}
+	sortOldestDataExportsFirst(exports)
+	return exports, nil
+}
+
+// FinishDataExport stores the outcome of building a claimed data export, with its archive when it
+// is ready. Exports that are not building are reported as sql.ErrNoRows.
+func (s *memoryStorage) FinishDataExport(exportID int, status domain.DataExportStatus, archive []byte, completedAt, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, exists := s.dataExports[exportID]
+	if !exists || e.export.Status != domain.DataExportBuilding {
+		return sql.ErrNoRows
+	}
+	e.export.Status = status
+	e.export.CompletedAt = &completedAt
+	e.export.ExpiresAt = &expiresAt
+	e.archive = slices.Clone(archive)
+	s.dataExports[exportID] = e
+	return nil
+}
+
+// DataExportArchive returns the ZIP archive of a data export of the user, other exports and
+// the ones without an archive are reported as sql.ErrNoRows.
+func (s *memoryStorage) DataExportArchive(userID int, exportID int) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, exists := s.dataExports[exportID]
+	if !exists || e.export.UserID != userID || e.archive == nil {
+		return nil, sql.ErrNoRows
+	}
+	return slices.Clone(e.archive), nil
+}
+
+// DeleteExpiredDataExports deletes the data exports that expired at the given time and returns how many.
+func (s *memoryStorage) DeleteExpiredDataExports(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for id, e := range s.dataExports {
+		if e.export.ExpiresAt != nil && !e.export.ExpiresAt.After(now) {
+			delete(s.dataExports, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyDataExport(export domain.DataExport) domain.DataExport {
+	if export.CompletedAt != nil {
+		completedAt := *export.CompletedAt
+		export.CompletedAt = &completedAt
+	}
+	if export.ExpiresAt != nil {
+		expiresAt := *export.ExpiresAt
+		export.ExpiresAt = &expiresAt
+	}
+	return export
+}
+
+func (s *memoryStorage) SaveWorkout(userID int, workout domain.Workout) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastWorkoutID++
+	workout = copyWorkout(workout)
+	workout.ID = s.lastWorkoutID
+	s.workouts[workout.ID] = memoryWorkout{userID: userID, workout: workout}
+	return workout.ID, nil
+}
+
+// SaveWorkoutSet appends a set to a workout, replacing it if the same exercise set was already logged.
+// SaveWorkoutSet logs a set in a workout, replacing the one with the same exercise and index.
+// Unknown and finished workouts are reported as sql.ErrNoRows.
+func (s *memoryStorage) SaveWorkoutSet(workoutID int, set domain.WorkoutSet) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, exists := s.workouts[workoutID]
+	if !exists || w.workout.FinishedAt != nil {
+		return sql.ErrNoRows
+	}
+	i := slices.IndexFunc(w.workout.Sets, func(logged domain.WorkoutSet) bool {
+		return logged.ExerciseID == set.ExerciseID && logged.SetIndex == set.SetIndex
+	})
+	if i >= 0 {
+		w.workout.Sets[i] = set
+	} else {
+		w.workout.Sets = append(w.workout.Sets, set)
+	}
+	s.workouts[workoutID] = w
+	return nil
+}
+
+// FinishWorkout closes a workout of the user, unknown and finished workouts are reported as sql.ErrNoRows.
+func (s *memoryStorage) FinishWorkout(userID int, workoutID int, finishedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, exists := s.workouts[workoutID]
+	if !exists || w.userID != userID || w.workout.FinishedAt != nil {
+		return sql.ErrNoRows
+	}
+	w.workout.FinishedAt = &finishedAt
+	s.workouts[workoutID] = w
+	return nil
+}
+
+// Workouts returns the workouts of the user, the most recent first.
+func (s *memoryStorage) Workouts(userID int) ([]domain.Workout, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	workouts := []domain.Workout{}
+	for _, w := range s.workouts {
+		if w.userID == userID {
+			workouts = append(workouts, copyWorkout(w.workout))
+		}
+	}
+	slices.SortFunc(workouts, func(a, b domain.Workout) int {
+		if c := b.StartedAt.Compare(a.StartedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return workouts, nil
+}
+
+func (s *memoryStorage) Workout(userID int, workoutID int) (domain.Workout, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w, exists := s.workouts[workoutID]
+	if !exists || w.userID != userID {
+		return domain.Workout{}, sql.ErrNoRows
+	}
+	return copyWorkout(w.workout), nil
+}
+
+func copyWorkout(workout domain.Workout) domain.Workout {
+	workout.Sets = append([]domain.WorkoutSet{}, workout.Sets...)
+	if workout.RoutineID != nil {
+		routineID := *workout.RoutineID
+		workout.RoutineID = &routineID
+	}
+	if workout.FinishedAt != nil {
+		finishedAt := *workout.FinishedAt
+		workout.FinishedAt = &finishedAt
+	}
+	return workout
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"fmt"
+	"gymlog/domain"
+	"sync"
+	"testing"
+)
+
+func TestMemoryStorageIsSafeForConcurrentUse(t *testing.T) {
+	store, err := NewMemoryStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			username := fmt.Sprintf("user%d", i)
+			if _, err := store.SaveUser(username, username+"@gymlog.test", "hash"); err != nil {
+				t.Error(err)
+				return
+			}
+			users, err := store.Users(username)
+			if err != nil || len(users) != 1 {
+				t.Errorf("users %s: got %v, %v", username, users, err)
+				return
+			}
+			for range 10 {
+				if _, err := store.SaveRoutine(users[0].ID, domain.Routine{Name: "routine", Exercises: []domain.ExerciseDetail{{ID: 1}}}); err != nil {
+					t.Error(err)
+				}
+				if _, err := store.Exercises(users[0].ID, domain.ExerciseQuery{Limit: 10}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	users, err := store.Users("user0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routines, err := store.Routines(users[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routines) != 10 {
+		t.Fatalf("got %d routines, want 10", len(routines))
+	}
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sqliteMigrations are the up migrations of the SQLite schema, applied in version order.
+// Applied migrations must never be edited: add a new file instead.
+//
+//go:embed migrations/sqlite/*.sql
+var sqliteMigrations embed.FS
+
+// postgresMigrations are the up migrations of the PostgreSQL schema, versioned independently of SQLite's.
+//
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// migration is a single schema change named "<version>_<name>.sql".
+type migration struct {
+	Version  int
+	Name     string
+	SQL      string
+	Checksum string
+}
+
+// loadMigrations reads and sorts the migrations of a directory, rejecting repeated versions.
+func loadMigrations(fsys fs.FS, dir string) ([]migration, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(files))
+	versions := make(map[int]string, len(files))
+	for _, file := range files {
+		base := path.Base(file)
+		prefix, name, found := strings.Cut(strings.TrimSuffix(base, ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if !found || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must look like 0001_description.sql", base)
+		}
+		if previous, exists := versions[version]; exists {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", previous, base)
+		}
+		versions[version] = base
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, migration{
+			Version:  version,
+			Name:     name,
+			SQL:      string(content),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// migrationDB is implemented by *sql.DB and *sql.Conn, so Postgres can migrate on the connection holding its lock.
+type migrationDB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// migrate applies the pending migrations, each one in its own transaction, and returns them.
+// The checksums of the already applied migrations must match the embedded files.
+// The SQL here must work on every supported database.
+func migrate(db migrationDB, migrations []migration) ([]migration, error) {
+	_, err := db.ExecContext(context.Background(), `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]migration, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = m
+	}
+	for version, checksum := range applied {
+		m, exists := known[version]
+		if !exists {
+			return nil, fmt.Errorf("database has migration %d applied, which this build does not know about", version)
+		}
+		if m.Checksum != checksum {
+			return nil, fmt.Errorf("migration %04d_%s was modified after being applied (checksum mismatch)", m.Version, m.Name)
+		}
+	}
+
+	var pending []migration
+	for _, m := range migrations {
+		if _, done := applied[m.Version]; done {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return pending, err
+		}
+		pending = append(pending, m)
+	}
+	return pending, nil
+}
+
+func appliedMigrations(db migrationDB) (map[int]string, error) {
+	rows, err := db.QueryContext(context.Background(), "SELECT version, checksum FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		applied[version] = checksum
+	}
+	return applied, rows.Err()
+}
+
+func applyMigration(db migrationDB, m migration) error {
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(m.SQL); err != nil {
+		return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	_, err = tx.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)", m.Version, m.Name, m.Checksum)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// migrationNames returns the file names of the migrations without extension, as shown by "gymlog migrate".
+func migrationNames(migrations []migration) []string {
+	names := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		names = append(names, fmt.Sprintf("%04d_%s", m.Version, m.Name))
+	}
+	return names
+}
@@ -0,0 +1,114 @@
+-- Esquema inicial de PostgreSQL, equivalente a las migraciones 0001 a 0006 de SQLite
+
+-- Tabla de usuarios
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(255) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabla de ejercicios: el catálogo global (user_id NULL) y los personalizados de cada usuario
+CREATE TABLE exercises (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(255) UNIQUE, -- Identificador estable del catálogo global, NULL en ejercicios personalizados
+    name VARCHAR(255) NOT NULL,
+    target VARCHAR(255) NOT NULL,
+    equipment VARCHAR(255), -- barbell, dumbbell, cable, bodyweight...
+    mechanics VARCHAR(255), -- compound o isolation
+    movement_pattern VARCHAR(255), -- squat, hinge, horizontal_push...
+    unilateral BOOLEAN NOT NULL DEFAULT FALSE,
+    instructions TEXT,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Grupos musculares
+CREATE TABLE muscles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE
+);
+
+-- Tabla de unión entre ejercicios y los músculos que trabajan
+CREATE TABLE exercise_muscles (
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    muscle_id INTEGER NOT NULL REFERENCES muscles(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL, -- primary o secondary
+    PRIMARY KEY (exercise_id, muscle_id)
+);
+
+-- Versiones aplicadas de los catálogos embebidos (por ahora solo el de ejercicios)
+CREATE TABLE catalog_versions (
+    name VARCHAR(255) PRIMARY KEY,
+    version INTEGER NOT NULL,
+    applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabla de rutinas (asociadas a usuarios)
+CREATE TABLE routines (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+-- Tabla de unión para la relación muchos-a-muchos entre rutinas y ejercicios
+CREATE TABLE routine_exercises (
+    routine_id INTEGER NOT NULL REFERENCES routines(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id),
+    order_index INTEGER NOT NULL, -- Para mantener el orden de los ejercicios en la rutina
+    sets INTEGER, -- Número de series para este ejercicio en esta rutina
+    reps INTEGER, -- Número de repeticiones para este ejercicio en esta rutina
+    PRIMARY KEY (routine_id, exercise_id)
+);
+
+-- Rutinas compartidas explícitamente con otros usuarios (solo lectura)
+CREATE TABLE routine_shares (
+    routine_id INTEGER NOT NULL REFERENCES routines(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- Usuario con el que se comparte la rutina
+    PRIMARY KEY (routine_id, user_id)
+);
+
+-- Tabla de sesiones
+CREATE TABLE sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    session_token VARCHAR(255) NOT NULL,
+    csrf_token VARCHAR(255) NOT NULL
+);
+
+-- Tabla de entrenamientos realizados (opcionalmente a partir de una rutina)
+CREATE TABLE workouts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    routine_id INTEGER REFERENCES routines(id) ON DELETE SET NULL, -- Rutina de origen, NULL si fue un entrenamiento libre
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ -- NULL mientras el entrenamiento sigue en curso
+);
+
+-- Series realizadas en cada entrenamiento
+CREATE TABLE workout_sets (
+    id SERIAL PRIMARY KEY, -- Mantiene el orden en que se registraron las series
+    workout_id INTEGER NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id),
+    set_index INTEGER NOT NULL, -- Número de serie dentro del ejercicio
+    reps INTEGER NOT NULL,
+    weight DOUBLE PRECISION NOT NULL DEFAULT 0, -- Peso en kg
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (workout_id, exercise_id, set_index)
+);
+
+-- Índices para mejorar el rendimiento de las consultas
+CREATE INDEX idx_exercises_user_id ON exercises(user_id);
+CREATE INDEX idx_exercises_target ON exercises(target);
+CREATE INDEX idx_exercises_equipment ON exercises(equipment);
+CREATE INDEX idx_exercises_lower_name ON exercises(lower(name), id);
+CREATE INDEX idx_exercise_muscles_muscle_id ON exercise_muscles(muscle_id);
+CREATE INDEX idx_routines_user_id ON routines(user_id);
+CREATE INDEX idx_routine_exercises_exercise_id ON routine_exercises(exercise_id);
+CREATE INDEX idx_routine_exercises_order ON routine_exercises(routine_id, order_index);
+CREATE INDEX idx_routine_shares_user_id ON routine_shares(user_id);
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_workouts_user_id ON workouts(user_id, started_at);
+CREATE INDEX idx_workout_sets_workout_id ON workout_sets(workout_id);
+CREATE INDEX idx_workout_sets_exercise_id ON workout_sets(exercise_id);
@@ -0,0 +1,8 @@
+-- Caducidad de las sesiones en el servidor, se renueva con cada uso
+ALTER TABLE sessions ADD COLUMN expires_at TIMESTAMPTZ;
+
+-- Las sesiones existentes duran lo mismo que sus cookies
+UPDATE sessions SET expires_at = CURRENT_TIMESTAMP + INTERVAL '1 day';
+ALTER TABLE sessions ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
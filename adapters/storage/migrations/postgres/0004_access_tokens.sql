@@ -0,0 +1,14 @@
+-- Tokens de acceso personales para scripts y la app, solo se guarda su hash
+CREATE TABLE access_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL, -- SHA-256 en hexadecimal
+    scope VARCHAR(16) NOT NULL CHECK (scope IN ('read', 'write')),
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ, -- NULL si no caduca
+    last_used_at TIMESTAMPTZ -- NULL si nunca se usó
+);
+
+CREATE UNIQUE INDEX idx_access_tokens_token_hash ON access_tokens(token_hash);
+CREATE INDEX idx_access_tokens_user_id ON access_tokens(user_id);
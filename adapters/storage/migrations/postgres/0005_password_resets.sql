@@ -0,0 +1,12 @@
+-- Solicitudes para restablecer la contraseña, solo se guarda el hash del token
+CREATE TABLE password_resets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL, -- SHA-256 en hexadecimal
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ -- NULL hasta que se usa, cada token sirve una sola vez
+);
+
+CREATE UNIQUE INDEX idx_password_resets_token_hash ON password_resets(token_hash);
+CREATE INDEX idx_password_resets_user_id ON password_resets(user_id);
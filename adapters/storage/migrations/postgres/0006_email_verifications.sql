@@ -0,0 +1,18 @@
+-- Fecha en que el usuario confirmó su email, NULL mientras no lo confirme.
+-- Los usuarios existentes nunca lo confirmaron, así que quedan sin verificar.
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- Tokens enviados para verificar el email, solo se guarda el hash del token
+CREATE TABLE email_verifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL, -- el email al que se envió, si el usuario lo cambia el token deja de valer
+    token_hash VARCHAR(64) NOT NULL, -- SHA-256 en hexadecimal
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ -- NULL hasta que se usa, cada token sirve una sola vez
+);
+
+CREATE UNIQUE INDEX idx_email_verifications_token_hash ON email_verifications(token_hash);
+-- Para contar los envíos recientes de cada usuario (límite de reenvíos)
+CREATE INDEX idx_email_verifications_user_id_created_at ON email_verifications(user_id, created_at);
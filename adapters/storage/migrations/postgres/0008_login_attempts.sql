@@ -0,0 +1,14 @@
+-- Registro de los intentos de login con contraseña, para frenar a quien prueba contraseñas
+-- y poder auditar los fallos. El username se guarda tal cual, aunque no exista el usuario.
+CREATE TABLE login_attempts (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    outcome VARCHAR(16) NOT NULL, -- 'succeeded', 'failed' o 'locked' (rechazado sin mirar la contraseña)
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_login_attempts_username_created_at ON login_attempts(username, created_at);
+CREATE INDEX idx_login_attempts_ip_created_at ON login_attempts(ip, created_at);
+-- Para borrar los intentos antiguos
+CREATE INDEX idx_login_attempts_created_at ON login_attempts(created_at);
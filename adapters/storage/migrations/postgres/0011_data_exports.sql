@@ -0,0 +1,15 @@
+-- Exportaciones de todos los datos de un usuario, se generan en segundo plano como un ZIP
+CREATE TABLE data_exports (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL, -- pending, ready o failed
+    created_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ, -- NULL mientras está pendiente
+    expires_at TIMESTAMPTZ, -- NULL mientras está pendiente, después se borra el archivo
+    archive BYTEA -- el ZIP, NULL hasta que está listo
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id);
+-- Para encontrar las exportaciones pendientes y las que ya caducaron
+CREATE INDEX idx_data_exports_status ON data_exports(status);
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at);
@@ -0,0 +1,5 @@
+-- Una rutina puede repetir un ejercicio, por ejemplo sentadillas al principio y al final, así que
+-- la clave pasa a ser la posición del ejercicio en la rutina.
+ALTER TABLE routine_exercises DROP CONSTRAINT routine_exercises_pkey;
+ALTER TABLE routine_exercises ADD PRIMARY KEY (routine_id, order_index);
+DROP INDEX idx_routine_exercises_order;
@@ -1,5 +1,5 @@
 -- Tabla de usuarios
-CREATE TABLE users (
+CREATE TABLE IF NOT EXISTS users (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     username VARCHAR(255) NOT NULL UNIQUE,
     email VARCHAR(255) NOT NULL UNIQUE,
@@ -8,14 +8,14 @@ CREATE TABLE users (
 );
 
 -- Tabla de ejercicios (compartidos entre todos los usuarios)
-CREATE TABLE exercises (
+CREATE TABLE IF NOT EXISTS exercises (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     name VARCHAR(255) NOT NULL,
     target VARCHAR(255) NOT NULL
 );
 
 -- Tabla de rutinas (ahora asociadas a usuarios)
-CREATE TABLE routines (
+CREATE TABLE IF NOT EXISTS routines (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     user_id INTEGER NOT NULL,
     name VARCHAR(255) NOT NULL,
@@ -24,7 +24,7 @@ CREATE TABLE routines (
 );
 
 -- Tabla de unión para la relación muchos-a-muchos entre rutinas y ejercicios
-CREATE TABLE routine_exercises (
+CREATE TABLE IF NOT EXISTS routine_exercises (
     routine_id INTEGER NOT NULL,
     exercise_id INTEGER NOT NULL,
     order_index INTEGER NOT NULL, -- Para mantener el orden de los ejercicios en la rutina
@@ -36,7 +36,7 @@ CREATE TABLE routine_exercises (
 );
 
 -- Tabla de sesiones
-CREATE TABLE sessions (
+CREATE TABLE IF NOT EXISTS sessions (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     user_id INTEGER NOT NULL,
     session_token VARCHAR(255) NOT NULL,
@@ -45,7 +45,7 @@ CREATE TABLE sessions (
 );
 
 -- Índices para mejorar el rendimiento de las consultas
-CREATE INDEX idx_routines_user_id ON routines(user_id);
-CREATE INDEX idx_routine_exercises_routine_id ON routine_exercises(routine_id);
-CREATE INDEX idx_routine_exercises_exercise_id ON routine_exercises(exercise_id);
-CREATE INDEX idx_routine_exercises_order ON routine_exercises(routine_id, order_index);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_routines_user_id ON routines(user_id);
+CREATE INDEX IF NOT EXISTS idx_routine_exercises_routine_id ON routine_exercises(routine_id);
+CREATE INDEX IF NOT EXISTS idx_routine_exercises_exercise_id ON routine_exercises(exercise_id);
+CREATE INDEX IF NOT EXISTS idx_routine_exercises_order ON routine_exercises(routine_id, order_index);
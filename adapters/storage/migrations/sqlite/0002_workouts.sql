@@ -0,0 +1,27 @@
+-- Tabla de entrenamientos realizados (opcionalmente a partir de una rutina)
+CREATE TABLE workouts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    routine_id INTEGER, -- Rutina de origen, NULL si fue un entrenamiento libre
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME, -- NULL mientras el entrenamiento sigue en curso
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE SET NULL
+);
+
+-- Series realizadas en cada entrenamiento
+CREATE TABLE workout_sets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT, -- Mantiene el orden en que se registraron las series
+    workout_id INTEGER NOT NULL,
+    exercise_id INTEGER NOT NULL,
+    set_index INTEGER NOT NULL, -- Número de serie dentro del ejercicio
+    reps INTEGER NOT NULL,
+    weight REAL NOT NULL DEFAULT 0, -- Peso en kg
+    completed BOOLEAN NOT NULL DEFAULT 0,
+    UNIQUE (workout_id, exercise_id, set_index),
+    FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE CASCADE,
+    FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_workouts_user_id ON workouts(user_id, started_at);
+CREATE INDEX idx_workout_sets_workout_id ON workout_sets(workout_id);
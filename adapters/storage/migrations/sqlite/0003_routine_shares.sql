@@ -0,0 +1,10 @@
+-- Rutinas compartidas explícitamente con otros usuarios (solo lectura)
+CREATE TABLE routine_shares (
+    routine_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL, -- Usuario con el que se comparte la rutina
+    PRIMARY KEY (routine_id, user_id),
+    FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_routine_shares_user_id ON routine_shares(user_id);
@@ -0,0 +1,4 @@
+-- NULL para el catálogo global, el dueño para ejercicios personalizados
+ALTER TABLE exercises ADD COLUMN user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_exercises_user_id ON exercises(user_id);
@@ -0,0 +1,26 @@
+-- Metadatos de los ejercicios, target sigue siendo el músculo principal
+ALTER TABLE exercises ADD COLUMN equipment VARCHAR(255); -- barbell, dumbbell, cable, bodyweight...
+ALTER TABLE exercises ADD COLUMN mechanics VARCHAR(255); -- compound o isolation
+ALTER TABLE exercises ADD COLUMN movement_pattern VARCHAR(255); -- squat, hinge, horizontal_push...
+ALTER TABLE exercises ADD COLUMN unilateral BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN instructions TEXT;
+
+-- Grupos musculares
+CREATE TABLE muscles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL UNIQUE
+);
+
+-- Tabla de unión entre ejercicios y los músculos que trabajan
+CREATE TABLE exercise_muscles (
+    exercise_id INTEGER NOT NULL,
+    muscle_id INTEGER NOT NULL,
+    role VARCHAR(16) NOT NULL, -- primary o secondary
+    PRIMARY KEY (exercise_id, muscle_id),
+    FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE,
+    FOREIGN KEY (muscle_id) REFERENCES muscles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_exercises_target ON exercises(target);
+CREATE INDEX idx_exercises_equipment ON exercises(equipment);
+CREATE INDEX idx_exercise_muscles_muscle_id ON exercise_muscles(muscle_id);
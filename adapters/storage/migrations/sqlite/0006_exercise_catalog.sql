@@ -0,0 +1,11 @@
+-- Identificador estable del catálogo global, NULL en ejercicios personalizados
+ALTER TABLE exercises ADD COLUMN slug VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_exercises_slug ON exercises(slug);
+
+-- Versiones aplicadas de los catálogos embebidos (por ahora solo el de ejercicios)
+CREATE TABLE catalog_versions (
+    name VARCHAR(255) PRIMARY KEY,
+    version INTEGER NOT NULL,
+    applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,12 @@
+-- Varias sesiones por usuario, cada una recuerda el dispositivo desde el que se inició
+ALTER TABLE sessions ADD COLUMN device_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN ip VARCHAR(64) NOT NULL DEFAULT ''; -- Última IP desde la que se usó
+ALTER TABLE sessions ADD COLUMN created_at DATETIME;
+ALTER TABLE sessions ADD COLUMN last_seen_at DATETIME;
+
+UPDATE sessions SET created_at = CURRENT_TIMESTAMP, last_seen_at = CURRENT_TIMESTAMP;
+
+-- Las sesiones se buscan por token al autenticar
+CREATE UNIQUE INDEX idx_sessions_session_token ON sessions(session_token);
+CREATE INDEX idx_sessions_user_id ON sessions(user_id, last_seen_at);
@@ -0,0 +1,7 @@
+-- Caducidad de las sesiones en el servidor, se renueva con cada uso
+ALTER TABLE sessions ADD COLUMN expires_at DATETIME;
+
+-- Las sesiones existentes duran lo mismo que sus cookies
+UPDATE sessions SET expires_at = datetime('now', '+1 day');
+
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
@@ -0,0 +1,15 @@
+-- Tokens de acceso personales para scripts y la app, solo se guarda su hash
+CREATE TABLE access_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL, -- SHA-256 en hexadecimal
+    scope VARCHAR(16) NOT NULL, -- read o write
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME, -- NULL si no caduca
+    last_used_at DATETIME, -- NULL si nunca se usó
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_access_tokens_token_hash ON access_tokens(token_hash);
+CREATE INDEX idx_access_tokens_user_id ON access_tokens(user_id);
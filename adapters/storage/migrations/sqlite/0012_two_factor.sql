@@ -0,0 +1,35 @@
+-- Autenticación en dos pasos con una app de códigos TOTP (RFC 6238), una por usuario.
+-- El secreto se guarda tal cual porque hace falta para calcular los códigos.
+CREATE TABLE user_totp (
+    user_id INTEGER PRIMARY KEY,
+    secret VARCHAR(64) NOT NULL, -- base32
+    created_at DATETIME NOT NULL,
+    confirmed_at DATETIME, -- NULL hasta que el usuario escribe un primer código, solo entonces se exige
+    last_step INTEGER NOT NULL DEFAULT 0, -- último paso TOTP usado, para no aceptar el mismo código dos veces
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Códigos de recuperación para entrar sin la app, solo se guarda su hash
+CREATE TABLE recovery_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    code_hash VARCHAR(64) NOT NULL, -- SHA-256 en hexadecimal
+    used_at DATETIME, -- NULL hasta que se usa, cada código sirve una sola vez
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes(user_id);
+
+-- Logins que pasaron la contraseña y esperan el segundo factor, duran pocos minutos
+CREATE TABLE mfa_challenges (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_hash VARCHAR(64) NOT NULL, -- SHA-256 en hexadecimal
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0, -- códigos incorrectos, con demasiados hay que volver a poner la contraseña
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_mfa_challenges_token_hash ON mfa_challenges(token_hash);
+CREATE INDEX idx_mfa_challenges_user_id ON mfa_challenges(user_id);
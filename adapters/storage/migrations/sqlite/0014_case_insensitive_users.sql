@@ -0,0 +1,4 @@
+-- Los usernames y emails son únicos sin distinguir mayúsculas: "Alice" y "alice" son el mismo usuario.
+-- Si ya hay usuarios repetidos de esa forma la migración falla, hay que resolverlos a mano antes.
+CREATE UNIQUE INDEX idx_users_username_lower ON users(lower(username));
+CREATE UNIQUE INDEX idx_users_email_lower ON users(lower(email));
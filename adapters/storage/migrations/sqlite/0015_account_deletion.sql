@@ -0,0 +1,6 @@
+-- Fecha en que se borrará la cuenta con todos sus datos, NULL si el usuario no pidió borrarla.
+-- Hasta esa fecha el usuario puede cancelar el borrado.
+ALTER TABLE users ADD COLUMN deletion_scheduled_at DATETIME;
+
+-- Para encontrar las cuentas que ya hay que borrar
+CREATE INDEX idx_users_deletion_scheduled_at ON users(deletion_scheduled_at);
@@ -0,0 +1,16 @@
+-- Exportaciones de todos los datos de un usuario, se generan en segundo plano como un ZIP
+CREATE TABLE data_exports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL, -- pending, ready o failed
+    created_at DATETIME NOT NULL,
+    completed_at DATETIME, -- NULL mientras está pendiente
+    expires_at DATETIME, -- NULL mientras está pendiente, después se borra el archivo
+    archive BLOB, -- el ZIP, NULL hasta que está listo
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id);
+-- Para encontrar las exportaciones pendientes y las que ya caducaron
+CREATE INDEX idx_data_exports_status ON data_exports(status);
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at);
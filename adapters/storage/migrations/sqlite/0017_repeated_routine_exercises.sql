@@ -0,0 +1,22 @@
+-- Una rutina puede repetir un ejercicio, por ejemplo sentadillas al principio y al final, así que
+-- la clave pasa a ser la posición del ejercicio en la rutina. SQLite no cambia claves primarias,
+-- hay que rehacer la tabla.
+CREATE TABLE routine_exercises_new (
+    routine_id INTEGER NOT NULL,
+    exercise_id INTEGER NOT NULL,
+    order_index INTEGER NOT NULL, -- Para mantener el orden de los ejercicios en la rutina
+    sets INTEGER, -- Número de series para este ejercicio en esta rutina
+    reps INTEGER, -- Número de repeticiones para este ejercicio en esta rutina
+    PRIMARY KEY (routine_id, order_index),
+    FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE,
+    FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE
+);
+
+INSERT INTO routine_exercises_new (routine_id, exercise_id, order_index, sets, reps)
+SELECT routine_id, exercise_id, order_index, sets, reps FROM routine_exercises;
+
+DROP TABLE routine_exercises;
+ALTER TABLE routine_exercises_new RENAME TO routine_exercises;
+
+CREATE INDEX idx_routine_exercises_routine_id ON routine_exercises(routine_id);
+CREATE INDEX idx_routine_exercises_exercise_id ON routine_exercises(exercise_id);
@@ -0,0 +1,3 @@
+-- Una exportación pasa de pending a building cuando un proceso la reclama para generarla, así dos
+-- procesos no generan la misma. Si el proceso muere, otro la vuelve a reclamar pasado un tiempo.
+ALTER TABLE data_exports ADD COLUMN claimed_at DATETIME; -- NULL hasta que se reclama
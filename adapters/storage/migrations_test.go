@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations(sqliteMigrations, "migrations/sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Fatalf("migration %s has version %d, want %d (no gaps)", m.Name, m.Version, i+1)
+		}
+	}
+
+	invalid := map[string]fstest.MapFS{
+		"bad name":         {"m/init.sql": {Data: []byte("SELECT 1;")}},
+		"repeated version": {"m/0001_a.sql": {Data: []byte("SELECT 1;")}, "m/1_b.sql": {Data: []byte("SELECT 1;")}},
+	}
+	for name, fsys := range invalid {
+		if _, err := loadMigrations(fsys, "m"); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMigrateAppliesPendingMigrationsOnce(t *testing.T) {
+	db := openTestDB(t)
+	fsys := fstest.MapFS{
+		"m/0001_users.sql": {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+	}
+	migrations, err := loadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied, err := migrate(db, migrations); err != nil || len(applied) != 1 {
+		t.Fatalf("first run: applied %v, err %v", applied, err)
+	}
+
+	fsys["m/0002_routines.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE routines (id INTEGER PRIMARY KEY);\nCREATE INDEX idx ON routines(id);")}
+	migrations, err = loadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := migrate(db, migrations)
+	if err != nil || len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("second run: applied %v, err %v", applied, err)
+	}
+	if applied, err := migrate(db, migrations); err != nil || len(applied) != 0 {
+		t.Fatalf("third run: applied %v, err %v", applied, err)
+	}
+}
+
+func TestMigrateRollsBackFailedMigrations(t *testing.T) {
+	db := openTestDB(t)
+	migrations, err := loadMigrations(fstest.MapFS{
+		"m/0001_users.sql":  {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+		"m/0002_broken.sql": {Data: []byte("CREATE TABLE routines (id INTEGER PRIMARY KEY);\nCREATE TABLE nope (;")},
+	}, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := migrate(db, migrations)
+	if err == nil || len(applied) != 1 {
+		t.Fatalf("applied %v, err %v", applied, err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'routines'").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatal("the failed migration was partially applied")
+	}
+}
+
+func TestMigrateRejectsModifiedMigrations(t *testing.T) {
+	db := openTestDB(t)
+	fsys := fstest.MapFS{
+		"m/0001_users.sql": {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+	}
+	migrations, _ := loadMigrations(fsys, "m")
+	if _, err := migrate(db, migrations); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys["m/0001_users.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);")}
+	migrations, _ = loadMigrations(fsys, "m")
+	if _, err := migrate(db, migrations); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("got %v, want a checksum error", err)
+	}
+
+	if _, err := migrate(db, nil); err == nil {
+		t.Fatal("expected an error for a database with unknown migrations")
+	}
+}
+
+func TestMigrateUpgradesBaselineDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "gymlog.db")
+	db, err := openSqliteDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Databases created by hand from the original schema.sql have no schema_migrations table.
+	baseline, err := sqliteMigrations.ReadFile("migrations/sqlite/0001_init.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(strings.ReplaceAll(string(baseline), "IF NOT EXISTS ", "")); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("INSERT INTO exercises (id, name, target) VALUES (1, '3/4 sit-up', 'abs')")
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := openSqlite(t, dbPath)
+	exercise, err := store.Exercise(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exercise.Slug != "3-4-sit-up" || exercise.Equipment == "" {
+		t.Fatalf("baseline exercise was not upgraded: %+v", exercise)
+	}
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := openSqliteDB(filepath.Join(t.TempDir(), "gymlog.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
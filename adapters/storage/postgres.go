@@ -0,0 +1,1385 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gymlog/domain"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Keys of the Postgres advisory locks that serialize the setup of instances starting at the same time.
+const (
+	postgresMigrationLock = 674_001
+	postgresCatalogLock   = 674_002
+)
+
+// postgresStorage is the implementation of the Storage interface for PostgreSQL.
+type postgresStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresStorage creates a new PostgreSQL storage, migrating the schema to the latest version first.
+func NewPostgresStorage(dsn string) (Storage, error) {
+	db, err := openPostgresDB(dsn)
+	if err != nil {
+		return nil, err
+	}
+	storage := &postgresStorage{db: db}
+	if err := storage.setup(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return storage, nil
+}
+
+// MigratePostgres applies the pending migrations to a PostgreSQL database and returns their names.
+func MigratePostgres(dsn string) ([]string, error) {
+	db, err := openPostgresDB(dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	applied, err := migratePostgres(db)
+	return migrationNames(applied), err
+}
+
+func openPostgresDB(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// migratePostgres applies the migrations holding an advisory lock, so several instances starting
+// at the same time wait for each other instead of applying the same migrations twice.
+func migratePostgres(db *sql.DB) ([]migration, error) {
+	migrations, err := loadMigrations(postgresMigrations, "migrations/postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", postgresMigrationLock); err != nil {
+		return nil, err
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", postgresMigrationLock)
+
+	return migrate(conn, migrations)
+}
+
+// setup migrates the schema and rolls out the exercise catalog.
+func (s *postgresStorage) setup() error {
+	if _, err := migratePostgres(s.db); err != nil {
+		return err
+	}
+	return s.seedExercises()
+}
+
+// seedExercises rolls out the embedded exercise catalog when the database has an older version.
+// Exercises are upserted by slug, so their IDs never change and routines keep pointing to them.
+func (s *postgresStorage) seedExercises() error {
+	catalog, err := parseExerciseCatalog(exerciseCatalogJSON)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", postgresCatalogLock); err != nil {
+		return err
+	}
+
+	var current int
+	err = tx.QueryRow("SELECT version FROM catalog_versions WHERE name = 'exercises'").Scan(&current)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if current >= catalog.Version {
+		return nil // Already seeded
+	}
+
+	for _, entry := range catalog.Exercises {
+		exerciseID, err := upsertPostgresCatalogExercise(tx, entry)
+		if err != nil {
+			return fmt.Errorf("seeding exercise %s: %w", entry.Slug, err)
+		}
+		if err := savePostgresExerciseMuscles(tx, exerciseID, entry.toDomain()); err != nil {
+			return fmt.Errorf("seeding exercise %s: %w", entry.Slug, err)
+		}
+	}
+
+	// The catalog IDs were inserted explicitly, so move the sequence past them.
+	_, err = tx.Exec("SELECT setval(pg_get_serial_sequence('exercises', 'id'), (SELECT COALESCE(MAX(id), 0) + 1 FROM exercises), false)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO catalog_versions (name, version, applied_at) VALUES ('exercises', $1, CURRENT_TIMESTAMP)
+		ON CONFLICT (name) DO UPDATE SET version = excluded.version, applied_at = excluded.applied_at`, catalog.Version)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// upsertPostgresCatalogExercise inserts or updates a global exercise by slug and returns its ID.
+// New exercises keep the catalog ID when it is free, so both databases share the same IDs.
+func upsertPostgresCatalogExercise(tx *sql.Tx, entry catalogExercise) (int, error) {
+	var preferredID any
+	if entry.ID != 0 {
+		var taken bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM exercises WHERE id = $1 AND slug IS DISTINCT FROM $2)", entry.ID, entry.Slug).Scan(&taken)
+		if err != nil {
+			return 0, err
+		}
+		if !taken {
+			preferredID = entry.ID
+		}
+	}
+
+	var exerciseID int
+	err := tx.QueryRow(`
+		INSERT INTO exercises (id, slug, name, target, equipment, mechanics, movement_pattern, unilateral, instructions)
+		VALUES (COALESCE($1::integer, nextval(pg_get_serial_sequence('exercises', 'id'))), $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (slug) DO UPDATE SET
+			name = excluded.name,
+			target = excluded.target,
+			equipment = excluded.equipment,
+			mechanics = excluded.mechanics,
+			movement_pattern = excluded.movement_pattern,
+			unilateral = excluded.unilateral,
+			instructions = excluded.instructions
+		RETURNING id`,
+		preferredID, entry.Slug, entry.Name, entry.Target, nullString(entry.Equipment), nullString(entry.Mechanics),
+		nullString(entry.MovementPattern), entry.Unilateral, nullString(entry.Instructions)).Scan(&exerciseID)
+	return exerciseID, err
+}
+
+func (s *postgresStorage) Close() error {
+	return s.db.Close()
+}
+
+// Exercises returns the global exercises plus the ones created by the user that match the query.
+func (s *postgresStorage) Exercises(userID int, query domain.ExerciseQuery) ([]domain.Exercise, error) {
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"(user_id IS NULL OR user_id = " + arg(userID) + ")"}
+	filters := []struct {
+		column string
+		value  string
+	}{
+		{"target", query.Target},
+		{"equipment", query.Equipment},
+		{"mechanics", query.Mechanics},
+		{"movement_pattern", query.MovementPattern},
+	}
+	for _, filter := range filters {
+		if filter.value != "" {
+			where = append(where, filter.column+" = "+arg(filter.value))
+		}
+	}
+	if query.Unilateral != nil {
+		where = append(where, "unilateral = "+arg(*query.Unilateral))
+	}
+	if query.Muscle != "" {
+		where = append(where, "id IN (SELECT em.exercise_id FROM exercise_muscles em JOIN muscles m ON m.id = em.muscle_id WHERE m.name = "+arg(query.Muscle)+")")
+	}
+	for _, term := range strings.Fields(query.Search) {
+		where = append(where, "name ILIKE "+arg("%"+likeEscaper.Replace(term)+"%")+` ESCAPE '\'`)
+	}
+
+	// Keyset pagination: the cursor holds the sort columns of the last exercise of the previous page.
+	if query.After != nil {
+		after := *query.After
+		after.Name = strings.ToLower(after.Name)
+		query.After = &after
+	}
+	columns, values := exerciseSortColumns(query, "lower(name)")
+	direction, comparison := "ASC", ">"
+	if query.Descending() {
+		direction, comparison = "DESC", "<"
+	}
+	if query.After != nil {
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			placeholders[i] = arg(value)
+		}
+		where = append(where, fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), comparison, strings.Join(placeholders, ", ")))
+	}
+	orderBy := make([]string, len(columns))
+	for i, column := range columns {
+		orderBy[i] = column + " " + direction
+	}
+
+	statement := fmt.Sprintf("SELECT "+exerciseColumns+" FROM exercises WHERE %s ORDER BY %s",
+		strings.Join(where, " AND "), strings.Join(orderBy, ", "))
+	if query.Limit > 0 {
+		statement += " LIMIT " + arg(query.Limit)
+	}
+
+	rows, err := s.db.Query(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := []domain.Exercise{}
+	for rows.Next() {
+		exercise, err := scanExercise(rows)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := s.loadExerciseMuscles(exercises); err != nil {
+		return nil, err
+	}
+	return exercises, nil
+}
+
+// Exercise returns an exercise visible to the user, other exercises are reported as sql.ErrNoRows.
+func (s *postgresStorage) Exercise(userID int, exerciseID int) (domain.Exercise, error) {
+	row := s.db.QueryRow("SELECT "+exerciseColumns+" FROM exercises WHERE id = $1 AND (user_id IS NULL OR user_id = $2)", exerciseID, userID)
+	exercise, err := scanExercise(row)
+	if err != nil {
+		return domain.Exercise{}, err
+	}
+	exercises := []domain.Exercise{exercise}
+	if err := s.loadExerciseMuscles(exercises); err != nil {
+		return domain.Exercise{}, err
+	}
+	return exercises[0], nil
+}
+
+func (s *postgresStorage) SaveExercise(userID int, exercise domain.Exercise) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var exerciseID int
+	err = tx.QueryRow(`
+		INSERT INTO exercises (name, target, equipment, mechanics, movement_pattern, unilateral, instructions, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		exercise.Name, exercise.Target, nullString(exercise.Equipment), nullString(exercise.Mechanics),
+		nullString(exercise.MovementPattern), exercise.Unilateral, nullString(exercise.Instructions), userID).Scan(&exerciseID)
+	if err != nil {
+		return 0, err
+	}
+	if err := savePostgresExerciseMuscles(tx, exerciseID, exercise); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return exerciseID, nil
+}
+
+// UpdateExercise updates an exercise created by the user, global exercises cannot be changed.
+func (s *postgresStorage) UpdateExercise(userID int, exercise domain.Exercise) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE exercises
+		SET name = $1, target = $2, equipment = $3, mechanics = $4, movement_pattern = $5, unilateral = $6, instructions = $7
+		WHERE id = $8 AND user_id = $9`,
+		exercise.Name, exercise.Target, nullString(exercise.Equipment), nullString(exercise.Mechanics),
+		nullString(exercise.MovementPattern), exercise.Unilateral, nullString(exercise.Instructions), exercise.ID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if err := savePostgresExerciseMuscles(tx, exercise.ID, exercise); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// savePostgresExerciseMuscles replaces the muscles of an exercise, creating the muscle groups that are missing.
+func savePostgresExerciseMuscles(tx *sql.Tx, exerciseID int, exercise domain.Exercise) error {
+	_, err := tx.Exec("DELETE FROM exercise_muscles WHERE exercise_id = $1", exerciseID)
+	if err != nil {
+		return err
+	}
+
+	roles := []struct {
+		role    string
+		muscles []string
+	}{
+		{"primary", exercise.PrimaryMuscles},
+		{"secondary", exercise.SecondaryMuscles},
+	}
+	for _, role := range roles {
+		for _, muscle := range role.muscles {
+			_, err = tx.Exec("INSERT INTO muscles (name) VALUES ($1) ON CONFLICT DO NOTHING", muscle)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(`
+				INSERT INTO exercise_muscles (exercise_id, muscle_id, role)
+				SELECT $1, id, $2 FROM muscles WHERE name = $3
+				ON CONFLICT DO NOTHING`, exerciseID, role.role, muscle)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadExerciseMuscles fills the primary and secondary muscles of the given exercises.
+func (s *postgresStorage) loadExerciseMuscles(exercises []domain.Exercise) error {
+	if len(exercises) == 0 {
+		return nil
+	}
+	byID := make(map[int]*domain.Exercise, len(exercises))
+	ids := make([]int64, 0, len(exercises))
+	for i := range exercises {
+		exercises[i].PrimaryMuscles = []string{}
+		exercises[i].SecondaryMuscles = []string{}
+		byID[exercises[i].ID] = &exercises[i]
+		ids = append(ids, int64(exercises[i].ID))
+	}
+
+	rows, err := s.db.Query(`
+		SELECT em.exercise_id, m.name, em.role
+		FROM exercise_muscles em
+		JOIN muscles m ON m.id = em.muscle_id
+		WHERE em.exercise_id = ANY($1)
+		ORDER BY em.exercise_id, em.role, m.name`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exerciseID int
+		var muscle, role string
+		if err := rows.Scan(&exerciseID, &muscle, &role); err != nil {
+			return err
+		}
+		exercise := byID[exerciseID]
+		if role == "primary" {
+			exercise.PrimaryMuscles = append(exercise.PrimaryMuscles, muscle)
+		} else {
+			exercise.SecondaryMuscles = append(exercise.SecondaryMuscles, muscle)
+		}
+	}
+	return rows.Err()
+}
+
+// DeleteExercise deletes an exercise created by the user as long as no routine or workout uses it.
+func (s *postgresStorage) DeleteExercise(userID int, exerciseID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locking the exercise keeps other transactions from adding it to a routine or workout meanwhile.
+	var id int
+	err = tx.QueryRow("SELECT id FROM exercises WHERE id = $1 AND user_id = $2 FOR UPDATE", exerciseID, userID).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	var inUse bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM routine_exercises WHERE exercise_id = $1)
+		OR EXISTS (SELECT 1 FROM workout_sets WHERE exercise_id = $1)`, exerciseID).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrExerciseInUse
+	}
+
+	// exercise_muscles is cleaned up by its foreign key.
+	_, err = tx.Exec("DELETE FROM exercises WHERE id = $1", exerciseID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *postgresStorage) SaveRoutine(userID int, routine domain.Routine) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var routineID int
+	err = tx.QueryRow("INSERT INTO routines (name, description, user_id) VALUES ($1, $2, $3) RETURNING id", routine.Name, routine.Description, userID).Scan(&routineID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := savePostgresRoutineExercises(tx, routineID, routine.Exercises); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return routineID, nil
+}
+
+// UpdateRoutine replaces the name, description and exercises of a routine owned by the user.
+// The exercises are rewritten so order_index always matches their position in the routine.
+func (s *postgresStorage) UpdateRoutine(userID int, routine domain.Routine) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE routines SET name = $1, description = $2 WHERE id = $3 AND user_id = $4", routine.Name, routine.Description, routine.ID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec("DELETE FROM routine_exercises WHERE routine_id = $1", routine.ID)
+	if err != nil {
+		return err
+	}
+	if err := savePostgresRoutineExercises(tx, routine.ID, routine.Exercises); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func savePostgresRoutineExercises(tx *sql.Tx, routineID int, exercises []domain.ExerciseDetail) error {
+	for i, exercise := range exercises {
+		_, err := tx.Exec("INSERT INTO routine_exercises (routine_id, exercise_id, order_index, sets, reps) VALUES ($1, $2, $3, $4, $5)", routineID, exercise.ID, i, exercise.Sets, exercise.Reps)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteRoutine deletes a routine owned by the user. The foreign keys delete its exercises and
+// shares and detach the workouts started from it.
+func (s *postgresStorage) DeleteRoutine(userID int, routineID int) error {
+	result, err := s.db.Exec("DELETE FROM routines WHERE id = $1 AND user_id = $2", routineID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ShareRoutine gives another user read access to a routine owned by the user.
+func (s *postgresStorage) ShareRoutine(userID int, routineID int, sharedWithUserID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var ownerID int
+	err = tx.QueryRow("SELECT user_id FROM routines WHERE id = $1 AND user_id = $2", routineID, userID).Scan(&ownerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO routine_shares (routine_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", routineID, sharedWithUserID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *postgresStorage) UnshareRoutine(userID int, routineID int, sharedWithUserID int) error {
+	result, err := s.db.Exec(`
+		DELETE FROM routine_shares
+		WHERE routine_id = $1 AND user_id = $2
+		AND routine_id IN (SELECT id FROM routines WHERE user_id = $3)`, routineID, sharedWithUserID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *postgresStorage) Users(username string) ([]domain.User, error) {
+	rows, err := s.db.Query("SELECT "+userColumns+" FROM users WHERE lower(username) = lower($1)", username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// User returns the user with the given ID, unknown users are reported as sql.ErrNoRows.
+func (s *postgresStorage) User(userID int) (domain.User, error) {
+	return scanUser(s.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", userID))
+}
+
+// UserByEmail returns the user with the given email, unknown emails are reported as sql.ErrNoRows.
+func (s *postgresStorage) UserByEmail(email string) (domain.User, error) {
+	return scanUser(s.db.QueryRow("SELECT "+userColumns+" FROM users WHERE lower(email) = lower($1)", email))
+}
+
+func (s *postgresStorage) SaveUser(username string, email string, passwordHash string) (int, error) {
+	var userID int
+	err := s.db.QueryRow("INSERT INTO users (username, email, password_hash) VALUES ($1, $2, $3) RETURNING id", username, email, passwordHash).Scan(&userID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		// unique_violation
+		return 0, userConstraintError(pqErr.Constraint, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// ChangePassword changes the password of the user, which logs out every other session, revokes
+// their access tokens and invalidates their password resets. Unknown users are reported as sql.ErrNoRows.
+func (s *postgresStorage) ChangePassword(userID int, passwordHash string, keepSessionID int, changedAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE users SET password_hash = $1 WHERE id = $2", passwordHash, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec("DELETE FROM sessions WHERE user_id = $1 AND id <> $2", userID, keepSessionID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM access_tokens WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL", changedAt, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ChangeEmail changes the email of the user, which has to be verified again. Emails of other
+// users are reported as ErrEmailTaken and unknown users as sql.ErrNoRows.
+func (s *postgresStorage) ChangeEmail(userID int, email string) error {
+	result, err := s.db.Exec("UPDATE users SET email = $1, email_verified_at = NULL WHERE id = $2", email, userID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return userConstraintError(pqErr.Constraint, err)
+	}
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ScheduleUserDeletion sets when the user will be deleted by DeleteScheduledUsers, nil cancels
+// the deletion. Unknown users are reported as sql.ErrNoRows.
+func (s *postgresStorage) ScheduleUserDeletion(userID int, deleteAt *time.Time) error {
+	result, err := s.db.Exec("UPDATE users SET deletion_scheduled_at = $1 WHERE id = $2", deleteAt, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteScheduledUsers deletes the users scheduled for deletion at the given time, with all their
+// data, and returns how many.
+func (s *postgresStorage) DeleteScheduledUsers(now time.Time) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Lock the users so a cancellation cannot land halfway through the deletion.
+	rows, err := tx.Query("SELECT id FROM users WHERE deletion_scheduled_at <= $1 FOR UPDATE", now)
+	if err != nil {
+		return 0, err
+	}
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	// The foreign keys delete the rest of the data, workouts and routines go first because their
+	// sets and exercises reference the custom exercises without cascading.
+	for _, table := range []string{"workouts", "routines"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ANY($1)", pq.Array(userIDs)); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM users WHERE id = ANY($1)", pq.Array(userIDs)); err != nil {
+		return 0, err
+	}
+	return len(userIDs), tx.Commit()
+}
+
+func (s *postgresStorage) SaveSession(session domain.UserSession) (int, error) {
+	var sessionID int
+	err := s.db.QueryRow(`
+		INSERT INTO sessions (user_id, session_token, csrf_token, device_name, user_agent, ip, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		session.UserID, session.SessionToken, session.CSRFToken, session.DeviceName, session.UserAgent, session.IP,
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt).Scan(&sessionID)
+	return sessionID, err
+}
+
+// Session returns the session with the given token, unknown tokens are reported as sql.ErrNoRows.
+func (s *postgresStorage) Session(sessionToken string) (domain.UserSession, error) {
+	row := s.db.QueryRow("SELECT "+sessionColumns+" FROM sessions WHERE session_token = $1", sessionToken)
+	return scanSession(row)
+}
+
+// Sessions returns the sessions of the user, the most recently used first.
+func (s *postgresStorage) Sessions(userID int) ([]domain.UserSession, error) {
+	rows, err := s.db.Query("SELECT "+sessionColumns+" FROM sessions WHERE user_id = $1 ORDER BY last_seen_at DESC, id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSessions(rows)
+}
+
+func (s *postgresStorage) TouchSession(sessionID int, lastSeenAt, expiresAt time.Time, ip string) error {
+	_, err := s.db.Exec("UPDATE sessions SET last_seen_at = $1, expires_at = $2, ip = $3 WHERE id = $4", lastSeenAt, expiresAt, ip, sessionID)
+	return err
+}
+
+// DeleteSession deletes a session of the user, other sessions are reported as sql.ErrNoRows.
+func (s *postgresStorage) DeleteSession(userID int, sessionID int) error {
+	result, err := s.db.Exec("DELETE FROM sessions WHERE id = $1 AND user_id = $2", sessionID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteOtherSessions deletes every session of the user except the given one.
+func (s *postgresStorage) DeleteOtherSessions(userID int, sessionID int) error {
+	_, err := s.db.Exec("DELETE FROM sessions WHERE user_id = $1 AND id <> $2", userID, sessionID)
+	return err
+}
+
+// DeleteExpiredSessions deletes the sessions that expired at the given time and returns how many.
+func (s *postgresStorage) DeleteExpiredSessions(now time.Time) (int, error) {
+	result, err := s.db.Exec("DELETE FROM sessions WHERE expires_at <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+// SaveLoginAttempt records a login attempt in the audit log.
+func (s *postgresStorage) SaveLoginAttempt(attempt domain.LoginAttempt) error {
+	_, err := s.db.Exec("INSERT INTO login_attempts (username, ip, outcome, created_at) VALUES ($1, $2, $3, $4)",
+		attempt.Username, attempt.IP, string(attempt.Outcome), attempt.CreatedAt)
+	return err
+}
+
+// LoginAttempts returns the last succeeded and failed logins of a username since the given
+// time, the newest first and at most limit of them.
+func (s *postgresStorage) LoginAttempts(username string, since time.Time, limit int) ([]domain.LoginAttempt, error) {
+	rows, err := s.db.Query("SELECT "+loginAttemptColumns+" FROM login_attempts WHERE username = $1 AND outcome <> $2 AND created_at >= $3 ORDER BY created_at DESC, id DESC LIMIT $4",
+		username, string(domain.LoginLocked), since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanLoginAttempts(rows)
+}
+
+// LoginFailuresByIP returns the last failed logins from an IP since the given time, the newest
+// first and at most limit of them.
+func (s *postgresStorage) LoginFailuresByIP(ip string, since time.Time, limit int) ([]domain.LoginAttempt, error) {
+	rows, err := s.db.Query("SELECT "+loginAttemptColumns+" FROM login_attempts WHERE ip = $1 AND outcome = $2 AND created_at >= $3 ORDER BY created_at DESC, id DESC LIMIT $4",
+		ip, string(domain.LoginFailed), since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanLoginAttempts(rows)
+}
+
+// DeleteLoginAttempts deletes the login attempts older than the given time and returns how many were deleted.
+func (s *postgresStorage) DeleteLoginAttempts(before time.Time) (int, error) {
+	result, err := s.db.Exec("DELETE FROM login_attempts WHERE created_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+func (s *postgresStorage) SaveAccessToken(token domain.AccessToken) (int, error) {
+	var tokenID int
+	err := s.db.QueryRow(`
+		INSERT INTO access_tokens (user_id, name, token_hash, scope, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		token.UserID, token.Name, token.TokenHash, string(token.Scope), token.CreatedAt, token.ExpiresAt).Scan(&tokenID)
+	return tokenID, err
+}
+
+// AccessToken returns the access token with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *postgresStorage) AccessToken(tokenHash string) (domain.AccessToken, error) {
+	row := s.db.QueryRow("SELECT "+accessTokenColumns+" FROM access_tokens WHERE token_hash = $1", tokenHash)
+	return scanAccessToken(row)
+}
+
+// AccessTokens returns the access tokens of the user, the newest first.
+func (s *postgresStorage) AccessTokens(userID int) ([]domain.AccessToken, error) {
+	rows, err := s.db.Query("SELECT "+accessTokenColumns+" FROM access_tokens WHERE user_id = $1 ORDER BY created_at DESC, id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.AccessToken{}
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (s *postgresStorage) TouchAccessToken(tokenID int, lastUsedAt time.Time) error {
+	_, err := s.db.Exec("UPDATE access_tokens SET last_used_at = $1 WHERE id = $2", lastUsedAt, tokenID)
+	return err
+}
+
+// DeleteAccessToken deletes an access token of the user, other tokens are reported as sql.ErrNoRows.
+func (s *postgresStorage) DeleteAccessToken(userID int, tokenID int) error {
+	result, err := s.db.Exec("DELETE FROM access_tokens WHERE id = $1 AND user_id = $2", tokenID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *postgresStorage) SavePasswordReset(reset domain.PasswordReset) (int, error) {
+	var resetID int
+	err := s.db.QueryRow("INSERT INTO password_resets (user_id, token_hash, created_at, expires_at) VALUES ($1, $2, $3, $4) RETURNING id",
+		reset.UserID, reset.TokenHash, reset.CreatedAt, reset.ExpiresAt).Scan(&resetID)
+	return resetID, err
+}
+
+// PasswordReset returns the password reset with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *postgresStorage) PasswordReset(tokenHash string) (domain.PasswordReset, error) {
+	return scanPasswordReset(s.db.QueryRow("SELECT "+passwordResetColumns+" FROM password_resets WHERE token_hash = $1", tokenHash))
+}
+
+// PasswordResets returns the password resets of the user created since the given time, the newest first.
+func (s *postgresStorage) PasswordResets(userID int, since time.Time) ([]domain.PasswordReset, error) {
+	rows, err := s.db.Query("SELECT "+passwordResetColumns+" FROM password_resets WHERE user_id = $1 AND created_at >= $2 ORDER BY created_at DESC, id DESC", userID, since)
+	if err != nil {
+		return nil, err
+	}
+	return scanPasswordResets(rows)
+}
+
+// ResetPassword uses a password reset to change the password of its user, which also logs them
+// out everywhere, revokes their access tokens and invalidates their other resets. Used resets are
+// reported as sql.ErrNoRows.
+func (s *postgresStorage) ResetPassword(resetID int, passwordHash string, usedAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRow("UPDATE password_resets SET used_at = $1 WHERE id = $2 AND used_at IS NULL RETURNING user_id", usedAt, resetID).Scan(&userID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE users SET password_hash = $1 WHERE id = $2", passwordHash, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM sessions WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM access_tokens WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL", usedAt, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *postgresStorage) SaveEmailVerification(verification domain.EmailVerification) (int, error) {
+	var verificationID int
+	err := s.db.QueryRow("INSERT INTO email_verifications (user_id, email, token_hash, created_at, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		verification.UserID, verification.Email, verification.TokenHash, verification.CreatedAt, verification.ExpiresAt).Scan(&verificationID)
+	return verificationID, err
+}
+
+// EmailVerification returns the email verification with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *postgresStorage) EmailVerification(tokenHash string) (domain.EmailVerification, error) {
+	row := s.db.QueryRow("SELECT "+emailVerificationColumns+" FROM email_verifications WHERE token_hash = $1", tokenHash)
+	return scanEmailVerification(row)
+}
+
+// EmailVerifications returns the email verifications sent to the user since the given time, the newest first.
+func (s *postgresStorage) EmailVerifications(userID int, since time.Time) ([]domain.EmailVerification, error) {
+	rows, err := s.db.Query("SELECT "+emailVerificationColumns+" FROM email_verifications WHERE user_id = $1 AND created_at >= $2 ORDER BY created_at DESC, id DESC", userID, since)
+	if err != nil {
+		return nil, err
+	}
+	return scanEmailVerifications(rows)
+}
+
+// VerifyEmail uses an email verification to mark the email of its user as verified and
+// invalidates their other verifications. Used verifications, and the ones sent to an email the
+// user no longer has, are reported as sql.ErrNoRows.
+func (s *postgresStorage) VerifyEmail(verificationID int, verifiedAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	var email string
+	err = tx.QueryRow("UPDATE email_verifications SET used_at = $1 WHERE id = $2 AND used_at IS NULL RETURNING user_id, email", verifiedAt, verificationID).Scan(&userID, &email)
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec("UPDATE users SET email_verified_at = $1 WHERE id = $2 AND email = $3", verifiedAt, userID, email)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec("UPDATE email_verifications SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL", verifiedAt, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// TOTP returns the authenticator app of the user, users without one are reported as sql.ErrNoRows.
+func (s *postgresStorage) TOTP(userID int) (domain.TOTP, error) {
+	var totp domain.TOTP
+	var confirmedAt sql.NullTime
+	err := s.db.QueryRow("SELECT user_id, secret, created_at, confirmed_at, last_step FROM user_totp WHERE user_id = $1", userID).
+		Scan(&totp.UserID, &totp.Secret, &totp.CreatedAt, &confirmedAt, &totp.LastStep)
+	if err != nil {
+		return domain.TOTP{}, err
+	}
+	if confirmedAt.Valid {
+		totp.ConfirmedAt = &confirmedAt.Time
+	}
+	return totp, nil
+}
+
+// SaveTOTP starts the enrollment of an authenticator app, replacing an unconfirmed one. A
+// confirmed app is kept and reported as sql.ErrNoRows, it has to be deleted first.
+func (s *postgresStorage) SaveTOTP(totp domain.TOTP) error {
+	result, err := s.db.Exec(`
+		INSERT INTO user_totp (user_id, secret, created_at, last_step) VALUES ($1, $2, $3, 0)
+		ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, created_at = excluded.created_at, last_step = 0
+		WHERE user_totp.confirmed_at IS NULL`,
+		totp.UserID, totp.Secret, totp.CreatedAt)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ConfirmTOTP enables the authenticator app of the user after its first code, of the given
+// step, and replaces their recovery codes. Apps that are missing or already confirmed are
+// reported as sql.ErrNoRows.
+func (s *postgresStorage) ConfirmTOTP(userID int, confirmedAt time.Time, step int64, recoveryCodeHashes []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE user_totp SET confirmed_at = $1, last_step = $2 WHERE user_id = $3 AND confirmed_at IS NULL", confirmedAt, step, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec("DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err := tx.Exec("INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)", userID, codeHash); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UseTOTPStep records that a code of the step was used. Steps that are not newer than the last
+// one used are reported as sql.ErrNoRows, so every code only works once.
+func (s *postgresStorage) UseTOTPStep(userID int, step int64) error {
+	result, err := s.db.Exec("UPDATE user_totp SET last_step = $1 WHERE user_id = $2 AND confirmed_at IS NOT NULL AND last_step < $3", step, userID, step)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UseRecoveryCode marks a recovery code of the user as used, unknown and used codes are
+// reported as sql.ErrNoRows.
+func (s *postgresStorage) UseRecoveryCode(userID int, codeHash string, usedAt time.Time) error {
+	result, err := s.db.Exec("UPDATE recovery_codes SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL", usedAt, userID, codeHash)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteTOTP disables two-factor authentication for the user, with their recovery codes and
+// pending logins.
+func (s *postgresStorage) DeleteTOTP(userID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"user_totp", "recovery_codes", "mfa_challenges"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveMFAChallenge saves a new pending login, replacing the one the user had.
+func (s *postgresStorage) SaveMFAChallenge(challenge domain.MFAChallenge) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM mfa_challenges WHERE user_id = $1", challenge.UserID); err != nil {
+		return 0, err
+	}
+	var challengeID int
+	err = tx.QueryRow("INSERT INTO mfa_challenges (user_id, token_hash, created_at, expires_at) VALUES ($1, $2, $3, $4) RETURNING id",
+		challenge.UserID, challenge.TokenHash, challenge.CreatedAt, challenge.ExpiresAt).Scan(&challengeID)
+	if err != nil {
+		return 0, err
+	}
+	return challengeID, tx.Commit()
+}
+
+// MFAChallenge returns the pending login with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *postgresStorage) MFAChallenge(tokenHash string) (domain.MFAChallenge, error) {
+	var challenge domain.MFAChallenge
+	err := s.db.QueryRow("SELECT id, user_id, token_hash, created_at, expires_at, attempts FROM mfa_challenges WHERE token_hash = $1", tokenHash).
+		Scan(&challenge.ID, &challenge.UserID, &challenge.TokenHash, &challenge.CreatedAt, &challenge.ExpiresAt, &challenge.Attempts)
+	return challenge, err
+}
+
+// FailMFAChallenge counts a wrong code typed for a pending login.
+func (s *postgresStorage) FailMFAChallenge(challengeID int) error {
+	_, err := s.db.Exec("UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = $1", challengeID)
+	return err
+}
+
+// DeleteMFAChallenge ends a pending login, unknown challenges are reported as sql.ErrNoRows so
+// that only one request can complete a login.
+func (s *postgresStorage) DeleteMFAChallenge(challengeID int) error {
+	result, err := s.db.Exec("DELETE FROM mfa_challenges WHERE id = $1", challengeID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SaveDataExport saves a new data export, which waits for FinishDataExport.
+func (s *postgresStorage) SaveDataExport(export domain.DataExport) (int, error) {
+	var exportID int
+	err := s.db.QueryRow("INSERT INTO data_exports (user_id, status, created_at) VALUES ($1, $2, $3) RETURNING id",
+		export.UserID, export.Status, export.CreatedAt).Scan(&exportID)
+	return exportID, err
+}
+
+// DataExport returns a data export of the user, other exports are reported as sql.ErrNoRows.
+func (s *postgresStorage) DataExport(userID int, exportID int) (domain.DataExport, error) {
+	row := s.db.QueryRow("SELECT "+dataExportColumns+" FROM data_exports WHERE id = $1 AND user_id = $2", exportID, userID)
+	return scanDataExport(row)
+}
+
+// DataExports returns the data exports of the user, the newest first.
+func (s *postgresStorage) DataExports(userID int) ([]domain.DataExport, error) {
+	rows, err := s.db.Query("SELECT "+dataExportColumns+" FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC, id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDataExports(rows)
+}
+
+// ClaimDataExports marks the pending data exports as building and returns them, the oldest first.
+// Exports left building since before staleBefore are claimed again, their builder died. Rows
+// another process is claiming are skipped instead of waited for.
+func (s *postgresStorage) ClaimDataExports(claimedAt, staleBefore time.Time) ([]domain.DataExport, error) {
+	rows, err := s.db.Query(`
+		UPDATE data_exports SET status = $1, claimed_at = $2
+		WHERE id IN (
+			SELECT id FROM data_exports
+			WHERE status = $3 OR (status = $1 AND claimed_at < $4)
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+dataExportColumns,
+		domain.DataExportBuilding, claimedAt, domain.DataExportPending, staleBefore)
+	if err != nil {
+		return nil, err
+	}
+	exports, err := scanDataExports(rows)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING gives no order.
+	sortOldestDataExportsFirst(exports)
+	return exports, nil
+}
+
+// FinishDataExport stores the outcome of building a claimed data export, with its archive when it
+// is ready. Exports that are not building are reported as sql.ErrNoRows.
+func (s *postgresStorage) FinishDataExport(exportID int, status domain.DataExportStatus, archive []byte, completedAt, expiresAt time.Time) error {
+	result, err := s.db.Exec("UPDATE data_exports SET status = $1, archive = $2, completed_at = $3, expires_at = $4 WHERE id = $5 AND status = $6",
+		status, archive, completedAt, expiresAt, exportID, domain.DataExportBuilding)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DataExportArchive returns the ZIP archive of a data export of the user, other exports and
+// the ones without an archive are reported as sql.ErrNoRows.
+func (s *postgresStorage) DataExportArchive(userID int, exportID int) ([]byte, error) {
+	var archive []byte
+	err := s.db.QueryRow("SELECT archive FROM data_exports WHERE id = $1 AND user_id = $2 AND archive IS NOT NULL", exportID, userID).Scan(&archive)
+	return archive, err
+}
+
+// DeleteExpiredDataExports deletes the data exports that expired at the given time and returns how many.
+func (s *postgresStorage) DeleteExpiredDataExports(now time.Time) (int, error) {
+	result, err := s.db.Exec("DELETE FROM data_exports WHERE expires_at <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+func (s *postgresStorage) Routines(userID int) ([]domain.Routine, error) {
+	rows, err := s.db.Query(`
+		SELECT r.id, r.user_id, r.name, r.description, re.exercise_id, re.sets, re.reps
+		FROM routines r
+		LEFT JOIN routine_exercises re ON r.id = re.routine_id
+		WHERE r.user_id = $1
+		ORDER BY r.id, re.order_index`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRoutines(rows)
+}
+
+// Routine returns a routine owned by or shared with the user, other routines are reported as sql.ErrNoRows.
+func (s *postgresStorage) Routine(userID int, routineID int) (domain.Routine, error) {
+	rows, err := s.db.Query(`
+		SELECT r.id, r.user_id, r.name, r.description, re.exercise_id, re.sets, re.reps
+		FROM routines r
+		LEFT JOIN routine_exercises re ON r.id = re.routine_id
+		WHERE r.id = $1 AND (r.user_id = $2 OR EXISTS (
+			SELECT 1 FROM routine_shares rs WHERE rs.routine_id = r.id AND rs.user_id = $2))
+		ORDER BY re.order_index`, routineID, userID)
+	if err != nil {
+		return domain.Routine{}, err
+	}
+	defer rows.Close()
+
+	routines, err := scanRoutines(rows)
+	if err != nil {
+		return domain.Routine{}, err
+	}
+	if len(routines) == 0 {
+		return domain.Routine{}, sql.ErrNoRows
+	}
+	return routines[0], nil
+}
+
+func (s *postgresStorage) SaveWorkout(userID int, workout domain.Workout) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var workoutID int
+	err = tx.QueryRow("INSERT INTO workouts (user_id, routine_id, started_at) VALUES ($1, $2, $3) RETURNING id", userID, workout.RoutineID, workout.StartedAt).Scan(&workoutID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, set := range workout.Sets {
+		_, err = tx.Exec("INSERT INTO workout_sets (workout_id, exercise_id, set_index, reps, weight, completed) VALUES ($1, $2, $3, $4, $5, $6)", workoutID, set.ExerciseID, set.SetIndex, set.Reps, set.Weight, set.Completed)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return workoutID, nil
+}
+
+// SaveWorkoutSet appends a set to a workout, replacing it if the same exercise set was already logged.
+// SaveWorkoutSet logs a set in a workout, replacing the one with the same exercise and index.
+// Unknown and finished workouts are reported as sql.ErrNoRows.
+func (s *postgresStorage) SaveWorkoutSet(workoutID int, set domain.WorkoutSet) error {
+	// FOR SHARE waits for a concurrent FinishWorkout and then sees the workout finished.
+	result, err := s.db.Exec(`
+		INSERT INTO workout_sets (workout_id, exercise_id, set_index, reps, weight, completed)
+		SELECT id, $2::integer, $3::integer, $4::integer, $5::double precision, $6::boolean
+		FROM workouts WHERE id = $1 AND finished_at IS NULL FOR SHARE
+		ON CONFLICT (workout_id, exercise_id, set_index)
+		DO UPDATE SET reps = excluded.reps, weight = excluded.weight, completed = excluded.completed`,
+		workoutID, set.ExerciseID, set.SetIndex, set.Reps, set.Weight, set.Completed)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// FinishWorkout closes a workout of the user, unknown and finished workouts are reported as sql.ErrNoRows.
+func (s *postgresStorage) FinishWorkout(userID int, workoutID int, finishedAt time.Time) error {
+	result, err := s.db.Exec("UPDATE workouts SET finished_at = $1 WHERE id = $2 AND user_id = $3 AND finished_at IS NULL", finishedAt, workoutID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *postgresStorage) Workouts(userID int) ([]domain.Workout, error) {
+	rows, err := s.db.Query(`
+		SELECT w.id, w.routine_id, w.started_at, w.finished_at, ws.exercise_id, ws.set_index, ws.reps, ws.weight, ws.completed
+		FROM workouts w
+		LEFT JOIN workout_sets ws ON w.id = ws.workout_id
+		WHERE w.user_id = $1
+		ORDER BY w.started_at DESC, w.id DESC, ws.id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanWorkouts(rows)
+}
+
+func (s *postgresStorage) Workout(userID int, workoutID int) (domain.Workout, error) {
+	rows, err := s.db.Query(`
+		SELECT w.id, w.routine_id, w.started_at, w.finished_at, ws.exercise_id, ws.set_index, ws.reps, ws.weight, ws.completed
+		FROM workouts w
+		LEFT JOIN workout_sets ws ON w.id = ws.workout_id
+		WHERE w.id = $1 AND w.user_id = $2
+		ORDER BY ws.id`, workoutID, userID)
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	defer rows.Close()
+
+	workouts, err := scanWorkouts(rows)
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	if len(workouts) == 0 {
+		return domain.Workout{}, sql.ErrNoRows
+	}
+	return workouts[0], nil
+}
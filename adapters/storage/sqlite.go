@@ -1,201 +1,1592 @@
 package storage
 
 import (
+	"cmp"
 	"database/sql"
-	_ "embed"
+	"errors"
+	"fmt"
 	"gymlog/domain"
+	"slices"
 	"strings"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
-//go:embed
-var exercisesSeedSQL string
-
 // sqliteStorage is the implementation of the Storage interface for SQLite.
 type sqliteStorage struct {
 	db *sql.DB
+	// fullTextSearch is true when SQLite was built with FTS5 (go build -tags sqlite_fts5).
+	fullTextSearch bool
+}
+
+// NewSqliteStorage creates a new SQLite storage, migrating the schema to the latest version first.
+func NewSqliteStorage(dbPath string) (Storage, error) {
+	db, err := openSqliteDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	storage := &sqliteStorage{db: db}
+	if err := storage.setup(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return storage, nil
+}
+
+// MigrateSqlite applies the pending migrations to a SQLite database and returns their names.
+func MigrateSqlite(dbPath string) ([]string, error) {
+	db, err := openSqliteDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	applied, err := migrateSqlite(db)
+	return migrationNames(applied), err
+}
+
+func openSqliteDB(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrateSqlite(db *sql.DB) ([]migration, error) {
+	migrations, err := loadMigrations(sqliteMigrations, "migrations/sqlite")
+	if err != nil {
+		return nil, err
+	}
+	return migrate(db, migrations)
+}
+
+// setup migrates the schema, rolls out the exercise catalog and prepares the search index.
+func (s *sqliteStorage) setup() error {
+	if _, err := migrateSqlite(s.db); err != nil {
+		return err
+	}
+	if err := s.seedExercises(); err != nil {
+		return err
+	}
+	return s.setupSearchIndex()
+}
+
+// seedExercises rolls out the embedded exercise catalog when the database has an older version.
+// Exercises are upserted by slug, so their IDs never change and routines keep pointing to them.
+func (s *sqliteStorage) seedExercises() error {
+	catalog, err := parseExerciseCatalog(exerciseCatalogJSON)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current int
+	err = tx.QueryRow("SELECT version FROM catalog_versions WHERE name = 'exercises'").Scan(&current)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if current >= catalog.Version {
+		return nil // Already seeded
+	}
+
+	for _, entry := range catalog.Exercises {
+		exerciseID, err := upsertCatalogExercise(tx, entry)
+		if err != nil {
+			return fmt.Errorf("seeding exercise %s: %w", entry.Slug, err)
+		}
+		if err := saveExerciseMuscles(tx, exerciseID, entry.toDomain()); err != nil {
+			return fmt.Errorf("seeding exercise %s: %w", entry.Slug, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO catalog_versions (name, version, applied_at) VALUES ('exercises', ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (name) DO UPDATE SET version = excluded.version, applied_at = excluded.applied_at`, catalog.Version)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// upsertCatalogExercise inserts or updates a global exercise by slug and returns its ID.
+func upsertCatalogExercise(tx *sql.Tx, entry catalogExercise) (int, error) {
+	var preferredID any
+	if entry.ID != 0 {
+		// Adopt the row inserted by the old SQL seed, which had the same ID but no slug.
+		_, err := tx.Exec("UPDATE exercises SET slug = ? WHERE id = ? AND slug IS NULL AND user_id IS NULL", entry.Slug, entry.ID)
+		if err != nil {
+			return 0, err
+		}
+		var taken bool
+		err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM exercises WHERE id = ? AND slug IS NOT ?)", entry.ID, entry.Slug).Scan(&taken)
+		if err != nil {
+			return 0, err
+		}
+		if !taken {
+			preferredID = entry.ID
+		}
+	}
+
+	var exerciseID int
+	err := tx.QueryRow(`
+		INSERT INTO exercises (id, slug, name, target, equipment, mechanics, movement_pattern, unilateral, instructions)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (slug) DO UPDATE SET
+			name = excluded.name,
+			target = excluded.target,
+			equipment = excluded.equipment,
+			mechanics = excluded.mechanics,
+			movement_pattern = excluded.movement_pattern,
+			unilateral = excluded.unilateral,
+			instructions = excluded.instructions
+		RETURNING id`,
+		preferredID, entry.Slug, entry.Name, entry.Target, nullString(entry.Equipment), nullString(entry.Mechanics),
+		nullString(entry.MovementPattern), entry.Unilateral, nullString(entry.Instructions)).Scan(&exerciseID)
+	return exerciseID, err
+}
+
+// setupSearchIndex keeps the exercises_fts index in sync with exercises when FTS5 is available.
+// Without FTS5 the triggers are dropped so writes keep working and name search falls back to LIKE.
+func (s *sqliteStorage) setupSearchIndex() error {
+	err := s.db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&s.fullTextSearch)
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		"DROP TRIGGER IF EXISTS exercises_fts_insert",
+		"DROP TRIGGER IF EXISTS exercises_fts_delete",
+		"DROP TRIGGER IF EXISTS exercises_fts_update",
+	}
+	if s.fullTextSearch {
+		statements = []string{
+			"CREATE VIRTUAL TABLE IF NOT EXISTS exercises_fts USING fts5(name, content='exercises', content_rowid='id')",
+			`CREATE TRIGGER IF NOT EXISTS exercises_fts_insert AFTER INSERT ON exercises BEGIN
+				INSERT INTO exercises_fts (rowid, name) VALUES (new.id, new.name);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS exercises_fts_delete AFTER DELETE ON exercises BEGIN
+				INSERT INTO exercises_fts (exercises_fts, rowid, name) VALUES ('delete', old.id, old.name);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS exercises_fts_update AFTER UPDATE OF name ON exercises BEGIN
+				INSERT INTO exercises_fts (exercises_fts, rowid, name) VALUES ('delete', old.id, old.name);
+				INSERT INTO exercises_fts (rowid, name) VALUES (new.id, new.name);
+			END`,
+			// Rebuild in case exercises changed while running without FTS5.
+			"INSERT INTO exercises_fts (exercises_fts) VALUES ('rebuild')",
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStorage) Close() error {
+	return s.db.Close()
+}
+
+// Exercises returns the global exercises plus the ones created by the user that match the query.
+func (s *sqliteStorage) Exercises(userID int, query domain.ExerciseQuery) ([]domain.Exercise, error) {
+	where := []string{"(user_id IS NULL OR user_id = ?)"}
+	args := []any{userID}
+
+	filters := []struct {
+		column string
+		value  string
+	}{
+		{"target", query.Target},
+		{"equipment", query.Equipment},
+		{"mechanics", query.Mechanics},
+		{"movement_pattern", query.MovementPattern},
+	}
+	for _, filter := range filters {
+		if filter.value != "" {
+			where = append(where, filter.column+" = ?")
+			args = append(args, filter.value)
+		}
+	}
+	if query.Unilateral != nil {
+		where = append(where, "unilateral = ?")
+		args = append(args, *query.Unilateral)
+	}
+	if query.Muscle != "" {
+		where = append(where, "id IN (SELECT em.exercise_id FROM exercise_muscles em JOIN muscles m ON m.id = em.muscle_id WHERE m.name = ?)")
+		args = append(args, query.Muscle)
+	}
+	if terms := strings.Fields(query.Search); len(terms) > 0 {
+		if s.fullTextSearch {
+			where = append(where, "id IN (SELECT rowid FROM exercises_fts WHERE exercises_fts MATCH ?)")
+			args = append(args, ftsMatchQuery(terms))
+		} else {
+			for _, term := range terms {
+				where = append(where, "name LIKE ? ESCAPE '\\'")
+				args = append(args, "%"+likeEscaper.Replace(term)+"%")
+			}
+		}
+	}
+
+	// Keyset pagination: the cursor holds the sort columns of the last exercise of the previous page.
+	columns, values := exerciseSortColumns(query, "name COLLATE NOCASE")
+	direction, comparison := "ASC", ">"
+	if query.Descending() {
+		direction, comparison = "DESC", "<"
+	}
+	if query.After != nil {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		where = append(where, fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), comparison, placeholders))
+		args = append(args, values...)
+	}
+	orderBy := make([]string, len(columns))
+	for i, column := range columns {
+		orderBy[i] = column + " " + direction
+	}
+
+	statement := fmt.Sprintf("SELECT "+exerciseColumns+" FROM exercises WHERE %s ORDER BY %s",
+		strings.Join(where, " AND "), strings.Join(orderBy, ", "))
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := s.db.Query(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := []domain.Exercise{}
+	for rows.Next() {
+		exercise, err := scanExercise(rows)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := s.loadExerciseMuscles(exercises); err != nil {
+		return nil, err
+	}
+	return exercises, nil
+}
+
+// exerciseSortColumns returns the ORDER BY columns of the query and the cursor values for them.
+// nameColumn is the case-insensitive name expression of the database and the ID always breaks ties.
+func exerciseSortColumns(query domain.ExerciseQuery, nameColumn string) ([]string, []any) {
+	var after domain.ExerciseCursor
+	if query.After != nil {
+		after = *query.After
+	}
+	switch query.Sort {
+	case domain.SortByTarget, domain.SortByTargetDesc:
+		return []string{"target", nameColumn, "id"}, []any{after.Target, after.Name, after.ID}
+	case domain.SortByID, domain.SortByIDDesc:
+		return []string{"id"}, []any{after.ID}
+	default:
+		return []string{nameColumn, "id"}, []any{after.Name, after.ID}
+	}
+}
+
+var likeEscaper = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+
+// ftsMatchQuery turns the search terms into an FTS5 query where every term is a quoted prefix.
+func ftsMatchQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Exercise returns an exercise visible to the user, other exercises are reported as sql.ErrNoRows.
+func (s *sqliteStorage) Exercise(userID int, exerciseID int) (domain.Exercise, error) {
+	row := s.db.QueryRow("SELECT "+exerciseColumns+" FROM exercises WHERE id = ? AND (user_id IS NULL OR user_id = ?)", exerciseID, userID)
+	exercise, err := scanExercise(row)
+	if err != nil {
+		return domain.Exercise{}, err
+	}
+	exercises := []domain.Exercise{exercise}
+	if err := s.loadExerciseMuscles(exercises); err != nil {
+		return domain.Exercise{}, err
+	}
+	return exercises[0], nil
+}
+
+func (s *sqliteStorage) SaveExercise(userID int, exercise domain.Exercise) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO exercises (name, target, equipment, mechanics, movement_pattern, unilateral, instructions, user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		exercise.Name, exercise.Target, nullString(exercise.Equipment), nullString(exercise.Mechanics),
+		nullString(exercise.MovementPattern), exercise.Unilateral, nullString(exercise.Instructions), userID)
+	if err != nil {
+		return 0, err
+	}
+	exerciseID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := saveExerciseMuscles(tx, int(exerciseID), exercise); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(exerciseID), nil
+}
+
+// UpdateExercise updates an exercise created by the user, global exercises cannot be changed.
+func (s *sqliteStorage) UpdateExercise(userID int, exercise domain.Exercise) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE exercises
+		SET name = ?, target = ?, equipment = ?, mechanics = ?, movement_pattern = ?, unilateral = ?, instructions = ?
+		WHERE id = ? AND user_id = ?`,
+		exercise.Name, exercise.Target, nullString(exercise.Equipment), nullString(exercise.Mechanics),
+		nullString(exercise.MovementPattern), exercise.Unilateral, nullString(exercise.Instructions), exercise.ID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if err := saveExerciseMuscles(tx, exercise.ID, exercise); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveExerciseMuscles replaces the muscles of an exercise, creating the muscle groups that are missing.
+func saveExerciseMuscles(tx *sql.Tx, exerciseID int, exercise domain.Exercise) error {
+	_, err := tx.Exec("DELETE FROM exercise_muscles WHERE exercise_id = ?", exerciseID)
+	if err != nil {
+		return err
+	}
+
+	roles := []struct {
+		role    string
+		muscles []string
+	}{
+		{"primary", exercise.PrimaryMuscles},
+		{"secondary", exercise.SecondaryMuscles},
+	}
+	for _, role := range roles {
+		for _, muscle := range role.muscles {
+			_, err = tx.Exec("INSERT OR IGNORE INTO muscles (name) VALUES (?)", muscle)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec("INSERT OR IGNORE INTO exercise_muscles (exercise_id, muscle_id, role) SELECT ?, id, ? FROM muscles WHERE name = ?", exerciseID, role.role, muscle)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadExerciseMuscles fills the primary and secondary muscles of the given exercises.
+func (s *sqliteStorage) loadExerciseMuscles(exercises []domain.Exercise) error {
+	if len(exercises) == 0 {
+		return nil
+	}
+	byID := make(map[int]*domain.Exercise, len(exercises))
+	args := make([]any, 0, len(exercises))
+	for i := range exercises {
+		exercises[i].PrimaryMuscles = []string{}
+		exercises[i].SecondaryMuscles = []string{}
+		byID[exercises[i].ID] = &exercises[i]
+		args = append(args, exercises[i].ID)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	rows, err := s.db.Query(`
+		SELECT em.exercise_id, m.name, em.role
+		FROM exercise_muscles em
+		JOIN muscles m ON m.id = em.muscle_id
+		WHERE em.exercise_id IN (`+placeholders+`)
+		ORDER BY em.exercise_id, em.role, m.name`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exerciseID int
+		var muscle, role string
+		if err := rows.Scan(&exerciseID, &muscle, &role); err != nil {
+			return err
+		}
+		exercise := byID[exerciseID]
+		if role == "primary" {
+			exercise.PrimaryMuscles = append(exercise.PrimaryMuscles, muscle)
+		} else {
+			exercise.SecondaryMuscles = append(exercise.SecondaryMuscles, muscle)
+		}
+	}
+	return rows.Err()
+}
+
+// DeleteExercise deletes an exercise created by the user as long as no routine or workout uses it.
+func (s *sqliteStorage) DeleteExercise(userID int, exerciseID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var inUse bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM routine_exercises WHERE exercise_id = ?)
+		OR EXISTS (SELECT 1 FROM workout_sets WHERE exercise_id = ?)`, exerciseID, exerciseID).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM exercises WHERE id = ? AND user_id = ?", exerciseID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if inUse {
+		return ErrExerciseInUse
+	}
+	_, err = tx.Exec("DELETE FROM exercise_muscles WHERE exercise_id = ?", exerciseID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// nullString stores empty strings as NULL.
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-// NewSqliteStorage creates a new SQLite storage.
-func NewSqliteStorage(dbPath string) (Storage, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+// exerciseColumns are the columns read by scanExercise, muscles are loaded separately.
+const exerciseColumns = "id, slug, name, target, equipment, mechanics, movement_pattern, unilateral, instructions, user_id"
+
+func scanExercise(row rowScanner) (domain.Exercise, error) {
+	var exercise domain.Exercise
+	var slug, equipment, mechanics, movementPattern, instructions sql.NullString
+	var ownerID sql.NullInt64
+	err := row.Scan(&exercise.ID, &slug, &exercise.Name, &exercise.Target, &equipment, &mechanics, &movementPattern,
+		&exercise.Unilateral, &instructions, &ownerID)
+	if err != nil {
+		return domain.Exercise{}, err
+	}
+	exercise.Slug = slug.String
+	exercise.Equipment = equipment.String
+	exercise.Mechanics = mechanics.String
+	exercise.MovementPattern = movementPattern.String
+	exercise.Instructions = instructions.String
+	if ownerID.Valid {
+		id := int(ownerID.Int64)
+		exercise.OwnerID = &id
+	}
+	return exercise, nil
+}
+
+func (s *sqliteStorage) SaveRoutine(userID int, routine domain.Routine) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO routines (name, description, user_id) VALUES (?, ?, ?)", routine.Name, routine.Description, userID)
+	if err != nil {
+		return 0, err
+	}
+	routineID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, exercise := range routine.Exercises {
+		_, err = tx.Exec("INSERT INTO routine_exercises (routine_id, exercise_id, order_index, sets, reps) VALUES (?, ?, ?, ?, ?)", routineID, exercise.ID, i, exercise.Sets, exercise.Reps)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(routineID), nil
+}
+
+// UpdateRoutine replaces the name, description and exercises of a routine owned by the user.
+// The exercises are rewritten so order_index always matches their position in the routine.
+func (s *sqliteStorage) UpdateRoutine(userID int, routine domain.Routine) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE routines SET name = ?, description = ? WHERE id = ? AND user_id = ?", routine.Name, routine.Description, routine.ID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec("DELETE FROM routine_exercises WHERE routine_id = ?", routine.ID)
+	if err != nil {
+		return err
+	}
+	for i, exercise := range routine.Exercises {
+		_, err = tx.Exec("INSERT INTO routine_exercises (routine_id, exercise_id, order_index, sets, reps) VALUES (?, ?, ?, ?, ?)", routine.ID, exercise.ID, i, exercise.Sets, exercise.Reps)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *sqliteStorage) DeleteRoutine(userID int, routineID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM routines WHERE id = ? AND user_id = ?", routineID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	// Foreign keys are not enforced by default in SQLite, so clean up the dependent rows by hand.
+	_, err = tx.Exec("DELETE FROM routine_exercises WHERE routine_id = ?", routineID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM routine_shares WHERE routine_id = ?", routineID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE workouts SET routine_id = NULL WHERE routine_id = ?", routineID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ShareRoutine gives another user read access to a routine owned by the user.
+func (s *sqliteStorage) ShareRoutine(userID int, routineID int, sharedWithUserID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var ownerID int
+	err = tx.QueryRow("SELECT user_id FROM routines WHERE id = ? AND user_id = ?", routineID, userID).Scan(&ownerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT OR IGNORE INTO routine_shares (routine_id, user_id) VALUES (?, ?)", routineID, sharedWithUserID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStorage) UnshareRoutine(userID int, routineID int, sharedWithUserID int) error {
+	result, err := s.db.Exec(`
+		DELETE FROM routine_shares
+		WHERE routine_id = ? AND user_id = ?
+		AND routine_id IN (SELECT id FROM routines WHERE user_id = ?)`, routineID, sharedWithUserID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// userColumns are the columns read by scanUser.
+const userColumns = "id, username, email, password_hash, email_verified_at, deletion_scheduled_at"
+
+func scanUser(row rowScanner) (domain.User, error) {
+	var user domain.User
+	var emailVerifiedAt, deletionScheduledAt sql.NullTime
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &emailVerifiedAt, &deletionScheduledAt)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
+	return user, nil
+}
+
+func (s *sqliteStorage) Users(username string) ([]domain.User, error) {
+	rows, err := s.db.Query("SELECT "+userColumns+" FROM users WHERE lower(username) = lower(?)", username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// User returns the user with the given ID, unknown users are reported as sql.ErrNoRows.
+func (s *sqliteStorage) User(userID int) (domain.User, error) {
+	return scanUser(s.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = ?", userID))
+}
+
+// UserByEmail returns the user with the given email, unknown emails are reported as sql.ErrNoRows.
+func (s *sqliteStorage) UserByEmail(email string) (domain.User, error) {
+	return scanUser(s.db.QueryRow("SELECT "+userColumns+" FROM users WHERE lower(email) = lower(?)", email))
+}
+
+func (s *sqliteStorage) SaveUser(username string, email string, passwordHash string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO users (username, email, password_hash) VALUES (?, ?, ?)", username, email, passwordHash)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		// The message names the constraint: "UNIQUE constraint failed: index 'idx_users_email_lower'"
+		return 0, userConstraintError(sqliteErr.Error(), err)
+	}
+	if err != nil {
+		return 0, err
+	}
+	userID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(userID), nil
+}
+
+// ChangePassword changes the password of the user, which logs out every other session, revokes
+// their access tokens and invalidates their password resets. Unknown users are reported as sql.ErrNoRows.
+func (s *sqliteStorage) ChangePassword(userID int, passwordHash string, keepSessionID int, changedAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE users SET password_hash = ? WHERE id = ?", passwordHash, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec("DELETE FROM sessions WHERE user_id = ? AND id <> ?", userID, keepSessionID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM access_tokens WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE password_resets SET used_at = ? WHERE user_id = ? AND used_at IS NULL", changedAt, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ChangeEmail changes the email of the user, which has to be verified again. Emails of other
+// users are reported as ErrEmailTaken and unknown users as sql.ErrNoRows.
+func (s *sqliteStorage) ChangeEmail(userID int, email string) error {
+	result, err := s.db.Exec("UPDATE users SET email = ?, email_verified_at = NULL WHERE id = ?", email, userID)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return userConstraintError(sqliteErr.Error(), err)
+	}
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ScheduleUserDeletion sets when the user will be deleted by DeleteScheduledUsers, nil cancels
+// the deletion. Unknown users are reported as sql.ErrNoRows.
+func (s *sqliteStorage) ScheduleUserDeletion(userID int, deleteAt *time.Time) error {
+	result, err := s.db.Exec("UPDATE users SET deletion_scheduled_at = ? WHERE id = ?", deleteAt, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteScheduledUsers deletes the users scheduled for deletion at the given time, with all their
+// data, and returns how many.
+func (s *sqliteStorage) DeleteScheduledUsers(now time.Time) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id FROM users WHERE deletion_scheduled_at <= ?", now)
+	if err != nil {
+		return 0, err
+	}
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Foreign keys are not enforced by default in SQLite, so delete the data of the users by hand.
+	statements := []string{
+		"DELETE FROM workout_sets WHERE workout_id IN (SELECT id FROM workouts WHERE user_id = ?)",
+		"DELETE FROM workouts WHERE user_id = ?",
+		"UPDATE workouts SET routine_id = NULL WHERE routine_id IN (SELECT id FROM routines WHERE user_id = ?)",
+		"DELETE FROM routine_exercises WHERE routine_id IN (SELECT id FROM routines WHERE user_id = ?)",
+		"DELETE FROM routine_shares WHERE routine_id IN (SELECT id FROM routines WHERE user_id = ?)",
+		"DELETE FROM routine_shares WHERE user_id = ?",
+		"DELETE FROM routines WHERE user_id = ?",
+		"DELETE FROM exercise_muscles WHERE exercise_id IN (SELECT id FROM exercises WHERE user_id = ?)",
+		"DELETE FROM exercises WHERE user_id = ?",
+		"DELETE FROM sessions WHERE user_id = ?",
+		"DELETE FROM access_tokens WHERE user_id = ?",
+		"DELETE FROM password_resets WHERE user_id = ?",
+		"DELETE FROM email_verifications WHERE user_id = ?",
+		"DELETE FROM user_totp WHERE user_id = ?",
+		"DELETE FROM recovery_codes WHERE user_id = ?",
+		"DELETE FROM mfa_challenges WHERE user_id = ?",
+		"DELETE FROM data_exports WHERE user_id = ?",
+		"DELETE FROM users WHERE id = ?",
+	}
+	for _, userID := range userIDs {
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement, userID); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(userIDs), tx.Commit()
+}
+
+func (s *sqliteStorage) SaveSession(session domain.UserSession) (int, error) {
+	result, err := s.db.Exec(`
+		INSERT INTO sessions (user_id, session_token, csrf_token, device_name, user_agent, ip, created_at, last_seen_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.UserID, session.SessionToken, session.CSRFToken, session.DeviceName, session.UserAgent, session.IP,
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	sessionID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(sessionID), nil
+}
+
+// Session returns the session with the given token, unknown tokens are reported as sql.ErrNoRows.
+func (s *sqliteStorage) Session(sessionToken string) (domain.UserSession, error) {
+	row := s.db.QueryRow("SELECT "+sessionColumns+" FROM sessions WHERE session_token = ?", sessionToken)
+	return scanSession(row)
+}
+
+// Sessions returns the sessions of the user, the most recently used first.
+func (s *sqliteStorage) Sessions(userID int) ([]domain.UserSession, error) {
+	rows, err := s.db.Query("SELECT "+sessionColumns+" FROM sessions WHERE user_id = ? ORDER BY last_seen_at DESC, id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSessions(rows)
+}
+
+func (s *sqliteStorage) TouchSession(sessionID int, lastSeenAt, expiresAt time.Time, ip string) error {
+	_, err := s.db.Exec("UPDATE sessions SET last_seen_at = ?, expires_at = ?, ip = ? WHERE id = ?", lastSeenAt, expiresAt, ip, sessionID)
+	return err
+}
+
+// DeleteSession deletes a session of the user, other sessions are reported as sql.ErrNoRows.
+func (s *sqliteStorage) DeleteSession(userID int, sessionID int) error {
+	result, err := s.db.Exec("DELETE FROM sessions WHERE id = ? AND user_id = ?", sessionID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteOtherSessions deletes every session of the user except the given one.
+func (s *sqliteStorage) DeleteOtherSessions(userID int, sessionID int) error {
+	_, err := s.db.Exec("DELETE FROM sessions WHERE user_id = ? AND id <> ?", userID, sessionID)
+	return err
+}
+
+// DeleteExpiredSessions deletes the sessions that expired at the given time and returns how many.
+func (s *sqliteStorage) DeleteExpiredSessions(now time.Time) (int, error) {
+	result, err := s.db.Exec("DELETE FROM sessions WHERE expires_at <= ?", now)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+// SaveLoginAttempt records a login attempt in the audit log.
+func (s *sqliteStorage) SaveLoginAttempt(attempt domain.LoginAttempt) error {
+	_, err := s.db.Exec("INSERT INTO login_attempts (username, ip, outcome, created_at) VALUES (?, ?, ?, ?)",
+		attempt.Username, attempt.IP, string(attempt.Outcome), attempt.CreatedAt)
+	return err
+}
+
+// LoginAttempts returns the last succeeded and failed logins of a username since the given
+// time, the newest first and at most limit of them.
+func (s *sqliteStorage) LoginAttempts(username string, since time.Time, limit int) ([]domain.LoginAttempt, error) {
+	rows, err := s.db.Query("SELECT "+loginAttemptColumns+" FROM login_attempts WHERE username = ? AND outcome <> ? AND created_at >= ? ORDER BY created_at DESC, id DESC LIMIT ?",
+		username, string(domain.LoginLocked), since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanLoginAttempts(rows)
+}
+
+// LoginFailuresByIP returns the last failed logins from an IP since the given time, the newest
+// first and at most limit of them.
+func (s *sqliteStorage) LoginFailuresByIP(ip string, since time.Time, limit int) ([]domain.LoginAttempt, error) {
+	rows, err := s.db.Query("SELECT "+loginAttemptColumns+" FROM login_attempts WHERE ip = ? AND outcome = ? AND created_at >= ? ORDER BY created_at DESC, id DESC LIMIT ?",
+		ip, string(domain.LoginFailed), since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanLoginAttempts(rows)
+}
+
+// DeleteLoginAttempts deletes the login attempts older than the given time and returns how many were deleted.
+func (s *sqliteStorage) DeleteLoginAttempts(before time.Time) (int, error) {
+	result, err := s.db.Exec("DELETE FROM login_attempts WHERE created_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+// loginAttemptColumns are the columns read by scanLoginAttempts.
+const loginAttemptColumns = "id, username, ip, outcome, created_at"
+
+func scanLoginAttempts(rows *sql.Rows) ([]domain.LoginAttempt, error) {
+	defer rows.Close()
+
+	attempts := []domain.LoginAttempt{}
+	for rows.Next() {
+		var attempt domain.LoginAttempt
+		var outcome string
+		err := rows.Scan(&attempt.ID, &attempt.Username, &attempt.IP, &outcome, &attempt.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		attempt.Outcome = domain.LoginOutcome(outcome)
+		attempts = append(attempts, attempt)
+	}
+	return attempts, rows.Err()
+}
+
+func (s *sqliteStorage) SaveAccessToken(token domain.AccessToken) (int, error) {
+	result, err := s.db.Exec(`
+		INSERT INTO access_tokens (user_id, name, token_hash, scope, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		token.UserID, token.Name, token.TokenHash, string(token.Scope), token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	tokenID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(tokenID), nil
+}
+
+// AccessToken returns the access token with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *sqliteStorage) AccessToken(tokenHash string) (domain.AccessToken, error) {
+	row := s.db.QueryRow("SELECT "+accessTokenColumns+" FROM access_tokens WHERE token_hash = ?", tokenHash)
+	return scanAccessToken(row)
+}
+
+// AccessTokens returns the access tokens of the user, the newest first.
+func (s *sqliteStorage) AccessTokens(userID int) ([]domain.AccessToken, error) {
+	rows, err := s.db.Query("SELECT "+accessTokenColumns+" FROM access_tokens WHERE user_id = ? ORDER BY created_at DESC, id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.AccessToken{}
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (s *sqliteStorage) TouchAccessToken(tokenID int, lastUsedAt time.Time) error {
+	_, err := s.db.Exec("UPDATE access_tokens SET last_used_at = ? WHERE id = ?", lastUsedAt, tokenID)
+	return err
+}
+
+// DeleteAccessToken deletes an access token of the user, other tokens are reported as sql.ErrNoRows.
+func (s *sqliteStorage) DeleteAccessToken(userID int, tokenID int) error {
+	result, err := s.db.Exec("DELETE FROM access_tokens WHERE id = ? AND user_id = ?", tokenID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *sqliteStorage) SavePasswordReset(reset domain.PasswordReset) (int, error) {
+	result, err := s.db.Exec("INSERT INTO password_resets (user_id, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?)",
+		reset.UserID, reset.TokenHash, reset.CreatedAt, reset.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	resetID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(resetID), nil
+}
+
+// PasswordReset returns the password reset with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *sqliteStorage) PasswordReset(tokenHash string) (domain.PasswordReset, error) {
+	return scanPasswordReset(s.db.QueryRow("SELECT "+passwordResetColumns+" FROM password_resets WHERE token_hash = ?", tokenHash))
+}
+
+// PasswordResets returns the password resets of the user created since the given time, the newest first.
+func (s *sqliteStorage) PasswordResets(userID int, since time.Time) ([]domain.PasswordReset, error) {
+	rows, err := s.db.Query("SELECT "+passwordResetColumns+" FROM password_resets WHERE user_id = ? AND created_at >= ? ORDER BY created_at DESC, id DESC", userID, since)
+	if err != nil {
+		return nil, err
+	}
+	return scanPasswordResets(rows)
+}
+
+// ResetPassword uses a password reset to change the password of its user, which also logs them
+// out everywhere, revokes their access tokens and invalidates their other resets. Used resets are
+// reported as sql.ErrNoRows.
+func (s *sqliteStorage) ResetPassword(resetID int, passwordHash string, usedAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRow("UPDATE password_resets SET used_at = ? WHERE id = ? AND used_at IS NULL RETURNING user_id", usedAt, resetID).Scan(&userID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE users SET password_hash = ? WHERE id = ?", passwordHash, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM sessions WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM access_tokens WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE password_resets SET used_at = ? WHERE user_id = ? AND used_at IS NULL", usedAt, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStorage) SaveEmailVerification(verification domain.EmailVerification) (int, error) {
+	result, err := s.db.Exec("INSERT INTO email_verifications (user_id, email, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?)",
+		verification.UserID, verification.Email, verification.TokenHash, verification.CreatedAt, verification.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	verificationID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(verificationID), nil
+}
+
+// EmailVerification returns the email verification with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *sqliteStorage) EmailVerification(tokenHash string) (domain.EmailVerification, error) {
+	row := s.db.QueryRow("SELECT "+emailVerificationColumns+" FROM email_verifications WHERE token_hash = ?", tokenHash)
+	return scanEmailVerification(row)
+}
+
+// EmailVerifications returns the email verifications sent to the user since the given time, the newest first.
+func (s *sqliteStorage) EmailVerifications(userID int, since time.Time) ([]domain.EmailVerification, error) {
+	rows, err := s.db.Query("SELECT "+emailVerificationColumns+" FROM email_verifications WHERE user_id = ? AND created_at >= ? ORDER BY created_at DESC, id DESC", userID, since)
+	if err != nil {
+		return nil, err
+	}
+	return scanEmailVerifications(rows)
+}
+
+// VerifyEmail uses an email verification to mark the email of its user as verified and
+// invalidates their other verifications. Used verifications, and the ones sent to an email the
+// user no longer has, are reported as sql.ErrNoRows.
+func (s *sqliteStorage) VerifyEmail(verificationID int, verifiedAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	var email string
+	err = tx.QueryRow("UPDATE email_verifications SET used_at = ? WHERE id = ? AND used_at IS NULL RETURNING user_id, email", verifiedAt, verificationID).Scan(&userID, &email)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if err := db.Ping(); err != nil {
-		return nil, err
+	result, err := tx.Exec("UPDATE users SET email_verified_at = ? WHERE id = ? AND email = ?", verifiedAt, userID, email)
+	if err != nil {
+		return err
 	}
-	storage := &sqliteStorage{db: db}
-	if err := storage.seedExercises(); err != nil {
-		return nil, err
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
 	}
-	return storage, nil
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec("UPDATE email_verifications SET used_at = ? WHERE user_id = ? AND used_at IS NULL", verifiedAt, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// seedExercises inserts default exercises if the table is empty.
-func (s *sqliteStorage) seedExercises() error {
-	var count int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM exercises").Scan(&count)
+// TOTP returns the authenticator app of the user, users without one are reported as sql.ErrNoRows.
+func (s *sqliteStorage) TOTP(userID int) (domain.TOTP, error) {
+	var totp domain.TOTP
+	var confirmedAt sql.NullTime
+	err := s.db.QueryRow("SELECT user_id, secret, created_at, confirmed_at, last_step FROM user_totp WHERE user_id = ?", userID).
+		Scan(&totp.UserID, &totp.Secret, &totp.CreatedAt, &confirmedAt, &totp.LastStep)
+	if err != nil {
+		return domain.TOTP{}, err
+	}
+	if confirmedAt.Valid {
+		totp.ConfirmedAt = &confirmedAt.Time
+	}
+	return totp, nil
+}
+
+// SaveTOTP starts the enrollment of an authenticator app, replacing an unconfirmed one. A
+// confirmed app is kept and reported as sql.ErrNoRows, it has to be deleted first.
+func (s *sqliteStorage) SaveTOTP(totp domain.TOTP) error {
+	result, err := s.db.Exec(`
+		INSERT INTO user_totp (user_id, secret, created_at, last_step) VALUES (?, ?, ?, 0)
+		ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, created_at = excluded.created_at, last_step = 0
+		WHERE user_totp.confirmed_at IS NULL`,
+		totp.UserID, totp.Secret, totp.CreatedAt)
 	if err != nil {
 		return err
 	}
-	if count > 0 {
-		return nil // Already seeded
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
 	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
+// ConfirmTOTP enables the authenticator app of the user after its first code, of the given
+// step, and replaces their recovery codes. Apps that are missing or already confirmed are
+// reported as sql.ErrNoRows.
+func (s *sqliteStorage) ConfirmTOTP(userID int, confirmedAt time.Time, step int64, recoveryCodeHashes []string) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Execute each INSERT statement from the embedded SQL file
-	statements := strings.Split(exercisesSeedSQL, "\n")
-	for _, stmt := range statements {
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue
-		}
-		_, err = tx.Exec(stmt)
-		if err != nil {
+	result, err := tx.Exec("UPDATE user_totp SET confirmed_at = ?, last_step = ? WHERE user_id = ? AND confirmed_at IS NULL", confirmedAt, step, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec("DELETE FROM recovery_codes WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err := tx.Exec("INSERT INTO recovery_codes (user_id, code_hash) VALUES (?, ?)", userID, codeHash); err != nil {
 			return err
 		}
 	}
 	return tx.Commit()
 }
 
-func (s *sqliteStorage) Close() error {
-	return s.db.Close()
+// UseTOTPStep records that a code of the step was used. Steps that are not newer than the last
+// one used are reported as sql.ErrNoRows, so every code only works once.
+func (s *sqliteStorage) UseTOTPStep(userID int, step int64) error {
+	result, err := s.db.Exec("UPDATE user_totp SET last_step = ? WHERE user_id = ? AND confirmed_at IS NOT NULL AND last_step < ?", step, userID, step)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-// Exercises returns all the exercises from the database.
-func (s *sqliteStorage) Exercises() ([]domain.Exercise, error) {
-	rows, err := s.db.Query("SELECT id, name, target FROM exercises")
+// UseRecoveryCode marks a recovery code of the user as used, unknown and used codes are
+// reported as sql.ErrNoRows.
+func (s *sqliteStorage) UseRecoveryCode(userID int, codeHash string, usedAt time.Time) error {
+	result, err := s.db.Exec("UPDATE recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL", usedAt, userID, codeHash)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
-	exercises := []domain.Exercise{}
-	for rows.Next() {
-		var exercise domain.Exercise
-		err = rows.Scan(&exercise.ID, &exercise.Name, &exercise.Target)
-		if err != nil {
-			return nil, err
+// DeleteTOTP disables two-factor authentication for the user, with their recovery codes and
+// pending logins.
+func (s *sqliteStorage) DeleteTOTP(userID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"user_totp", "recovery_codes", "mfa_challenges"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", userID); err != nil {
+			return err
 		}
-		exercises = append(exercises, exercise)
 	}
-	return exercises, nil
+	return tx.Commit()
 }
 
-func (s *sqliteStorage) SaveRoutine(userID int, routine domain.Routine) error {
+// SaveMFAChallenge saves a new pending login, replacing the one the user had.
+func (s *sqliteStorage) SaveMFAChallenge(challenge domain.MFAChallenge) (int, error) {
 	tx, err := s.db.Begin()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer tx.Rollback()
 
-	result, err := tx.Exec("INSERT INTO routines (name, description, user_id) VALUES (?, ?, ?)", routine.Name, routine.Description, userID)
+	if _, err := tx.Exec("DELETE FROM mfa_challenges WHERE user_id = ?", challenge.UserID); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("INSERT INTO mfa_challenges (user_id, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?)",
+		challenge.UserID, challenge.TokenHash, challenge.CreatedAt, challenge.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	challengeID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(challengeID), tx.Commit()
+}
+
+// MFAChallenge returns the pending login with the given hash, unknown hashes are reported as sql.ErrNoRows.
+func (s *sqliteStorage) MFAChallenge(tokenHash string) (domain.MFAChallenge, error) {
+	var challenge domain.MFAChallenge
+	err := s.db.QueryRow("SELECT id, user_id, token_hash, created_at, expires_at, attempts FROM mfa_challenges WHERE token_hash = ?", tokenHash).
+		Scan(&challenge.ID, &challenge.UserID, &challenge.TokenHash, &challenge.CreatedAt, &challenge.ExpiresAt, &challenge.Attempts)
+	return challenge, err
+}
+
+// FailMFAChallenge counts a wrong code typed for a pending login.
+func (s *sqliteStorage) FailMFAChallenge(challengeID int) error {
+	_, err := s.db.Exec("UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = ?", challengeID)
+	return err
+}
+
+// DeleteMFAChallenge ends a pending login, unknown challenges are reported as sql.ErrNoRows so
+// that only one request can complete a login.
+func (s *sqliteStorage) DeleteMFAChallenge(challengeID int) error {
+	result, err := s.db.Exec("DELETE FROM mfa_challenges WHERE id = ?", challengeID)
 	if err != nil {
 		return err
 	}
-	routineID, err := result.LastInsertId()
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
-	for i, exercise := range routine.Exercises {
-		_, err = tx.Exec("INSERT INTO routine_exercises (routine_id, exercise_id, order_index, sets, reps) VALUES (?, ?, ?, ?, ?)", routineID, exercise.ID, i, exercise.Sets, exercise.Reps)
+// dataExportColumns are the columns read by scanDataExport, the archive is only read to download it.
+const dataExportColumns = "id, user_id, status, created_at, completed_at, expires_at"
+
+func scanDataExport(row rowScanner) (domain.DataExport, error) {
+	var export domain.DataExport
+	var completedAt, expiresAt sql.NullTime
+	err := row.Scan(&export.ID, &export.UserID, &export.Status, &export.CreatedAt, &completedAt, &expiresAt)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+	return export, nil
+}
+
+func sortOldestDataExportsFirst(exports []domain.DataExport) {
+	slices.SortFunc(exports, func(a, b domain.DataExport) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+func scanDataExports(rows *sql.Rows) ([]domain.DataExport, error) {
+	defer rows.Close()
+
+	exports := []domain.DataExport{}
+	for rows.Next() {
+		export, err := scanDataExport(rows)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		exports = append(exports, export)
 	}
-
-	return tx.Commit()
+	return exports, rows.Err()
 }
 
-func (s *sqliteStorage) Users(username string) ([]domain.User, error) {
-	rows, err := s.db.Query("SELECT id, username, email, password_hash FROM users WHERE username = ?", username)
+// passwordResetColumns are the columns read by scanPasswordReset.
+const passwordResetColumns = "id, user_id, token_hash, created_at, expires_at, used_at"
+
+func scanPasswordReset(row rowScanner) (domain.PasswordReset, error) {
+	var reset domain.PasswordReset
+	var usedAt sql.NullTime
+	err := row.Scan(&reset.ID, &reset.UserID, &reset.TokenHash, &reset.CreatedAt, &reset.ExpiresAt, &usedAt)
 	if err != nil {
-		return nil, err
+		return domain.PasswordReset{}, err
+	}
+	if usedAt.Valid {
+		reset.UsedAt = &usedAt.Time
 	}
+	return reset, nil
+}
+
+func scanPasswordResets(rows *sql.Rows) ([]domain.PasswordReset, error) {
 	defer rows.Close()
 
-	users := []domain.User{}
+	resets := []domain.PasswordReset{}
 	for rows.Next() {
-		var user domain.User
-		err = rows.Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash)
+		reset, err := scanPasswordReset(rows)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, user)
+		resets = append(resets, reset)
 	}
-	return users, nil
+	return resets, rows.Err()
 }
 
-func (s *sqliteStorage) SaveUser(username string, email string, passwordHash string) error {
-	tx, err := s.db.Begin()
+// emailVerificationColumns are the columns read by scanEmailVerification.
+const emailVerificationColumns = "id, user_id, email, token_hash, created_at, expires_at, used_at"
+
+func scanEmailVerification(row rowScanner) (domain.EmailVerification, error) {
+	var verification domain.EmailVerification
+	var usedAt sql.NullTime
+	err := row.Scan(&verification.ID, &verification.UserID, &verification.Email, &verification.TokenHash,
+		&verification.CreatedAt, &verification.ExpiresAt, &usedAt)
 	if err != nil {
-		return err
+		return domain.EmailVerification{}, err
 	}
-	defer tx.Rollback()
+	if usedAt.Valid {
+		verification.UsedAt = &usedAt.Time
+	}
+	return verification, nil
+}
 
-	_, err = tx.Exec("INSERT INTO users (username, email, password_hash) VALUES (?, ?, ?)", username, email, passwordHash)
-	if err != nil {
-		return err
+func scanEmailVerifications(rows *sql.Rows) ([]domain.EmailVerification, error) {
+	defer rows.Close()
+
+	verifications := []domain.EmailVerification{}
+	for rows.Next() {
+		verification, err := scanEmailVerification(rows)
+		if err != nil {
+			return nil, err
+		}
+		verifications = append(verifications, verification)
 	}
-	return tx.Commit()
+	return verifications, rows.Err()
 }
 
-func (s *sqliteStorage) SaveSession(userID int, sessionToken string, csrfToken string) error {
-	tx, err := s.db.Begin()
+// accessTokenColumns are the columns read by scanAccessToken.
+const accessTokenColumns = "id, user_id, name, token_hash, scope, created_at, expires_at, last_used_at"
+
+func scanAccessToken(row rowScanner) (domain.AccessToken, error) {
+	var token domain.AccessToken
+	var scope string
+	var expiresAt, lastUsedAt sql.NullTime
+	err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenHash, &scope, &token.CreatedAt, &expiresAt, &lastUsedAt)
 	if err != nil {
-		return err
+		return domain.AccessToken{}, err
 	}
-	defer tx.Rollback()
+	token.Scope = domain.TokenScope(scope)
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	return token, nil
+}
+
+// sessionColumns are the columns read by scanSession.
+const sessionColumns = "id, user_id, session_token, csrf_token, device_name, user_agent, ip, created_at, last_seen_at, expires_at"
 
-	// Delete any existing sessions for this user to maintain only one session
-	_, err = tx.Exec("DELETE FROM sessions WHERE user_id = ?", userID)
+func scanSession(row rowScanner) (domain.UserSession, error) {
+	var session domain.UserSession
+	err := row.Scan(&session.ID, &session.UserID, &session.SessionToken, &session.CSRFToken, &session.DeviceName,
+		&session.UserAgent, &session.IP, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt)
 	if err != nil {
-		return err
+		return domain.UserSession{}, err
+	}
+	return session, nil
+}
+
+func scanSessions(rows *sql.Rows) ([]domain.UserSession, error) {
+	sessions := []domain.UserSession{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
 	}
+	return sessions, rows.Err()
+}
 
-	_, err = tx.Exec("INSERT INTO sessions (user_id, session_token, csrf_token) VALUES (?, ?, ?)", userID, sessionToken, csrfToken)
+// SaveDataExport saves a new data export, which waits for FinishDataExport.
+func (s *sqliteStorage) SaveDataExport(export domain.DataExport) (int, error) {
+	result, err := s.db.Exec("INSERT INTO data_exports (user_id, status, created_at) VALUES (?, ?, ?)",
+		export.UserID, export.Status, export.CreatedAt)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return tx.Commit()
+	exportID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(exportID), nil
 }
 
-func (s *sqliteStorage) GetUserSession(userID int) (domain.UserSession, error) {
-	row := s.db.QueryRow("SELECT session_token, csrf_token FROM sessions WHERE user_id = ?", userID)
+// DataExport returns a data export of the user, other exports are reported as sql.ErrNoRows.
+func (s *sqliteStorage) DataExport(userID int, exportID int) (domain.DataExport, error) {
+	row := s.db.QueryRow("SELECT "+dataExportColumns+" FROM data_exports WHERE id = ? AND user_id = ?", exportID, userID)
+	return scanDataExport(row)
+}
 
-	var domainUserSession domain.UserSession
-	err := row.Scan(&domainUserSession.SessionToken, &domainUserSession.CSRFToken)
+// DataExports returns the data exports of the user, the newest first.
+func (s *sqliteStorage) DataExports(userID int) ([]domain.DataExport, error) {
+	rows, err := s.db.Query("SELECT "+dataExportColumns+" FROM data_exports WHERE user_id = ? ORDER BY created_at DESC, id DESC", userID)
 	if err != nil {
-		return domain.UserSession{}, err
+		return nil, err
 	}
-	domainUserSession.UserID = userID
-	return domainUserSession, nil
+	return scanDataExports(rows)
 }
 
-func (s *sqliteStorage) DeleteSession(userID int) error {
-	tx, err := s.db.Begin()
+// ClaimDataExports marks the pending data exports as building and returns them, the oldest first.
+// Exports left building since before staleBefore are claimed again, their builder died.
+func (s *sqliteStorage) ClaimDataExports(claimedAt, staleBefore time.Time) ([]domain.DataExport, error) {
+	rows, err := s.db.Query("UPDATE data_exports SET status = ?, claimed_at = ? WHERE status = ? OR (status = ? AND claimed_at < ?) RETURNING "+dataExportColumns,
+		domain.DataExportBuilding, claimedAt, domain.DataExportPending, domain.DataExportBuilding, staleBefore)
+	if err != nil {
+		return nil, err
+	}
+	exports, err := scanDataExports(rows)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING gives no order.
+	sortOldestDataExportsFirst(exports)
+	return exports, nil
+}
+
+// FinishDataExport stores the outcome of building a claimed data export, with its archive when it
+// is ready. Exports that are not building are reported as sql.ErrNoRows.
+func (s *sqliteStorage) FinishDataExport(exportID int, status domain.DataExportStatus, archive []byte, completedAt, expiresAt time.Time) error {
+	result, err := s.db.Exec("UPDATE data_exports SET status = ?, archive = ?, completed_at = ?, expires_at = ? WHERE id = ? AND status = ?",
+		status, archive, completedAt, expiresAt, exportID, domain.DataExportBuilding)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
-	_, err = tx.Exec("DELETE FROM sessions WHERE user_id = ?", userID)
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
-	return tx.Commit()
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DataExportArchive returns the ZIP archive of a data export of the user, other exports and
+// the ones without an archive are reported as sql.ErrNoRows.
+func (s *sqliteStorage) DataExportArchive(userID int, exportID int) ([]byte, error) {
+	var archive []byte
+	err := s.db.QueryRow("SELECT archive FROM data_exports WHERE id = ? AND user_id = ? AND archive IS NOT NULL", exportID, userID).Scan(&archive)
+	return archive, err
+}
+
+// DeleteExpiredDataExports deletes the data exports that expired at the given time and returns how many.
+func (s *sqliteStorage) DeleteExpiredDataExports(now time.Time) (int, error) {
+	result, err := s.db.Exec("DELETE FROM data_exports WHERE expires_at <= ?", now)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
 }
 
 func (s *sqliteStorage) Routines(userID int) ([]domain.Routine, error) {
 	rows, err := s.db.Query(`
-		SELECT r.id, r.name, r.description, re.exercise_id, re.sets, re.reps 
+		SELECT r.id, r.user_id, r.name, r.description, re.exercise_id, re.sets, re.reps 
 		FROM routines r 
 		LEFT JOIN routine_exercises re ON r.id = re.routine_id 
 		WHERE r.user_id = ? 
@@ -205,23 +1596,52 @@ func (s *sqliteStorage) Routines(userID int) ([]domain.Routine, error) {
 	}
 	defer rows.Close()
 
+	return scanRoutines(rows)
+}
+
+// Routine returns a routine owned by or shared with the user, other routines are reported as sql.ErrNoRows.
+func (s *sqliteStorage) Routine(userID int, routineID int) (domain.Routine, error) {
+	rows, err := s.db.Query(`
+		SELECT r.id, r.user_id, r.name, r.description, re.exercise_id, re.sets, re.reps 
+		FROM routines r 
+		LEFT JOIN routine_exercises re ON r.id = re.routine_id 
+		WHERE r.id = ? AND (r.user_id = ? OR EXISTS (
+			SELECT 1 FROM routine_shares rs WHERE rs.routine_id = r.id AND rs.user_id = ?))
+		ORDER BY re.order_index`, routineID, userID, userID)
+	if err != nil {
+		return domain.Routine{}, err
+	}
+	defer rows.Close()
+
+	routines, err := scanRoutines(rows)
+	if err != nil {
+		return domain.Routine{}, err
+	}
+	if len(routines) == 0 {
+		return domain.Routine{}, sql.ErrNoRows
+	}
+	return routines[0], nil
+}
+
+// scanRoutines groups the rows of a routines/routine_exercises join into routines, keeping the query order.
+func scanRoutines(rows *sql.Rows) ([]domain.Routine, error) {
 	routineMap := make(map[int]*domain.Routine)
 	var routineOrder []int
 
 	for rows.Next() {
-		var routineID int
+		var routineID, userID int
 		var name, description string
 		var exerciseID, sets, reps sql.NullInt64
 
-		err = rows.Scan(&routineID, &name, &description, &exerciseID, &sets, &reps)
+		err := rows.Scan(&routineID, &userID, &name, &description, &exerciseID, &sets, &reps)
 		if err != nil {
 			return nil, err
 		}
 
-		// Check if we've seen this routine before
 		if _, exists := routineMap[routineID]; !exists {
 			routineMap[routineID] = &domain.Routine{
 				ID:          routineID,
+				UserID:      userID,
 				Name:        name,
 				Description: description,
 				Exercises:   []domain.ExerciseDetail{},
@@ -238,8 +1658,10 @@ func (s *sqliteStorage) Routines(userID int) ([]domain.Routine, error) {
 			})
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	// Build result slice maintaining order
 	routines := make([]domain.Routine, 0, len(routineOrder))
 	for _, id := range routineOrder {
 		routines = append(routines, *routineMap[id])
@@ -247,53 +1669,165 @@ func (s *sqliteStorage) Routines(userID int) ([]domain.Routine, error) {
 	return routines, nil
 }
 
-func (s *sqliteStorage) Routine(routineID int) (domain.Routine, error) {
+func (s *sqliteStorage) SaveWorkout(userID int, workout domain.Workout) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO workouts (user_id, routine_id, started_at) VALUES (?, ?, ?)", userID, workout.RoutineID, workout.StartedAt)
+	if err != nil {
+		return 0, err
+	}
+	workoutID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, set := range workout.Sets {
+		_, err = tx.Exec("INSERT INTO workout_sets (workout_id, exercise_id, set_index, reps, weight, completed) VALUES (?, ?, ?, ?, ?, ?)", workoutID, set.ExerciseID, set.SetIndex, set.Reps, set.Weight, set.Completed)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(workoutID), nil
+}
+
+// SaveWorkoutSet appends a set to a workout, replacing it if the same exercise set was already logged.
+// SaveWorkoutSet logs a set in a workout, replacing the one with the same exercise and index.
+// Unknown and finished workouts are reported as sql.ErrNoRows.
+func (s *sqliteStorage) SaveWorkoutSet(workoutID int, set domain.WorkoutSet) error {
+	result, err := s.db.Exec(`
+		INSERT INTO workout_sets (workout_id, exercise_id, set_index, reps, weight, completed)
+		SELECT id, ?, ?, ?, ?, ? FROM workouts WHERE id = ? AND finished_at IS NULL
+		ON CONFLICT (workout_id, exercise_id, set_index)
+		DO UPDATE SET reps = excluded.reps, weight = excluded.weight, completed = excluded.completed`,
+		set.ExerciseID, set.SetIndex, set.Reps, set.Weight, set.Completed, workoutID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// FinishWorkout closes a workout of the user, unknown and finished workouts are reported as sql.ErrNoRows.
+func (s *sqliteStorage) FinishWorkout(userID int, workoutID int, finishedAt time.Time) error {
+	result, err := s.db.Exec("UPDATE workouts SET finished_at = ? WHERE id = ? AND user_id = ? AND finished_at IS NULL", finishedAt, workoutID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *sqliteStorage) Workouts(userID int) ([]domain.Workout, error) {
 	rows, err := s.db.Query(`
-		SELECT r.id, r.name, r.description, re.exercise_id, re.sets, re.reps 
-		FROM routines r 
-		LEFT JOIN routine_exercises re ON r.id = re.routine_id 
-		WHERE r.id = ? 
-		ORDER BY re.order_index`, routineID)
+		SELECT w.id, w.routine_id, w.started_at, w.finished_at, ws.exercise_id, ws.set_index, ws.reps, ws.weight, ws.completed
+		FROM workouts w
+		LEFT JOIN workout_sets ws ON w.id = ws.workout_id
+		WHERE w.user_id = ?
+		ORDER BY w.started_at DESC, w.id DESC, ws.id`, userID)
 	if err != nil {
-		return domain.Routine{}, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanWorkouts(rows)
+}
+
+func (s *sqliteStorage) Workout(userID int, workoutID int) (domain.Workout, error) {
+	rows, err := s.db.Query(`
+		SELECT w.id, w.routine_id, w.started_at, w.finished_at, ws.exercise_id, ws.set_index, ws.reps, ws.weight, ws.completed
+		FROM workouts w
+		LEFT JOIN workout_sets ws ON w.id = ws.workout_id
+		WHERE w.id = ? AND w.user_id = ?
+		ORDER BY ws.id`, workoutID, userID)
+	if err != nil {
+		return domain.Workout{}, err
 	}
 	defer rows.Close()
 
-	var routine domain.Routine
-	found := false
+	workouts, err := scanWorkouts(rows)
+	if err != nil {
+		return domain.Workout{}, err
+	}
+	if len(workouts) == 0 {
+		return domain.Workout{}, sql.ErrNoRows
+	}
+	return workouts[0], nil
+}
+
+// scanWorkouts groups the rows of a workouts/workout_sets join into workouts, keeping the query order.
+func scanWorkouts(rows *sql.Rows) ([]domain.Workout, error) {
+	workoutMap := make(map[int]*domain.Workout)
+	var workoutOrder []int
 
 	for rows.Next() {
-		var id int
-		var name, description string
-		var exerciseID, sets, reps sql.NullInt64
+		var workoutID int
+		var routineID sql.NullInt64
+		var startedAt time.Time
+		var finishedAt sql.NullTime
+		var exerciseID, setIndex, reps sql.NullInt64
+		var weight sql.NullFloat64
+		var completed sql.NullBool
 
-		err = rows.Scan(&id, &name, &description, &exerciseID, &sets, &reps)
+		err := rows.Scan(&workoutID, &routineID, &startedAt, &finishedAt, &exerciseID, &setIndex, &reps, &weight, &completed)
 		if err != nil {
-			return domain.Routine{}, err
+			return nil, err
 		}
 
-		if !found {
-			routine = domain.Routine{
-				ID:          id,
-				Name:        name,
-				Description: description,
-				Exercises:   []domain.ExerciseDetail{},
+		if _, exists := workoutMap[workoutID]; !exists {
+			workout := &domain.Workout{
+				ID:        workoutID,
+				StartedAt: startedAt,
+				Sets:      []domain.WorkoutSet{},
+			}
+			if routineID.Valid {
+				id := int(routineID.Int64)
+				workout.RoutineID = &id
 			}
-			found = true
+			if finishedAt.Valid {
+				workout.FinishedAt = &finishedAt.Time
+			}
+			workoutMap[workoutID] = workout
+			workoutOrder = append(workoutOrder, workoutID)
 		}
 
+		// Add set if it exists (LEFT JOIN may return NULLs)
 		if exerciseID.Valid {
-			routine.Exercises = append(routine.Exercises, domain.ExerciseDetail{
-				ID:   int(exerciseID.Int64),
-				Sets: int(sets.Int64),
-				Reps: int(reps.Int64),
+			workoutMap[workoutID].Sets = append(workoutMap[workoutID].Sets, domain.WorkoutSet{
+				ExerciseID: int(exerciseID.Int64),
+				SetIndex:   int(setIndex.Int64),
+				Reps:       int(reps.Int64),
+				Weight:     weight.Float64,
+				Completed:  completed.Bool,
 			})
 		}
 	}
-
-	if !found {
-		return domain.Routine{}, sql.ErrNoRows
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return routine, nil
+	workouts := make([]domain.Workout, 0, len(workoutOrder))
+	for _, id := range workoutOrder {
+		workouts = append(workouts, *workoutMap[id])
+	}
+	return workouts, nil
 }
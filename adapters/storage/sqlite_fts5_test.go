@@ -0,0 +1,57 @@
+//go:build sqlite_fts5
+
+package storage
+
+import (
+	"fmt"
+	"gymlog/domain"
+	"path/filepath"
+	"testing"
+)
+
+// TestSqliteFullTextSearch runs the name search through FTS5, the other tests use it too when
+// built with the sqlite_fts5 tag but cannot tell it apart from the LIKE fallback.
+func TestSqliteFullTextSearch(t *testing.T) {
+	withCatalog(t, testCatalog)
+	store := openSqlite(t, filepath.Join(t.TempDir(), "gymlog.db"))
+	if !store.fullTextSearch {
+		t.Fatal("FTS5 is not enabled")
+	}
+
+	aliceID := saveTestUser(t, store, "alice")
+	exercise := domain.Exercise{Name: "Zercher squat", Target: "quads", PrimaryMuscles: []string{"quads"}}
+	exerciseID, err := store.SaveExercise(aliceID, exercise)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(terms string, want ...string) {
+		t.Helper()
+
+		query := domain.ExerciseQuery{ExerciseFilter: domain.ExerciseFilter{Search: terms}}
+		exercises, err := store.Exercises(aliceID, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := exerciseNames(exercises); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("search %q: got %v, want %v", terms, got, want)
+		}
+	}
+	search("squ", "Barbell squat", "bulgarian split squat", "Zercher squat")
+	search("zerch squ", "Zercher squat")
+	// Quotes are escaped instead of ending the FTS5 string.
+	search(`zercher"`, "Zercher squat")
+
+	// The triggers keep the index in sync with renamed and deleted exercises.
+	exercise.ID = exerciseID
+	exercise.Name = "Anderson squat"
+	if err := store.UpdateExercise(aliceID, exercise); err != nil {
+		t.Fatal(err)
+	}
+	search("zerch")
+	search("anders", "Anderson squat")
+	if err := store.DeleteExercise(aliceID, exerciseID); err != nil {
+		t.Fatal(err)
+	}
+	search("anders")
+}
@@ -1,17 +1,187 @@
 package storage
 
-import "gymlog/domain"
+import (
+	"errors"
+	"gymlog/domain"
+	"strings"
+	"time"
+)
 
-// Storage is the interface for the storage layer.
+// ErrExerciseInUse is returned when deleting an exercise that routines or workouts still reference.
+var ErrExerciseInUse = errors.New("exercise is used by routines or workouts")
+
+// ErrUsernameTaken and ErrEmailTaken are returned when saving a user with the username or email
+// of another user, ignoring case.
+var (
+	ErrUsernameTaken = errors.New("username is already taken")
+	ErrEmailTaken    = errors.New("email is already taken")
+)
+
+// userConstraintError returns the error for the violation of a unique constraint of the users
+// table, named after its column like "users.username" or "idx_users_email_lower".
+func userConstraintError(constraint string, err error) error {
+	switch {
+	case strings.Contains(constraint, "username"):
+		return ErrUsernameTaken
+	case strings.Contains(constraint, "email"):
+		return ErrEmailTaken
+	}
+	return err
+}
+
+// Storage is the interface for the storage layer, made of one interface per kind of data so
+// the code that only needs some of them can ask for less.
 type Storage interface {
 	Close() error
-	Exercises() ([]domain.Exercise, error)
-	SaveRoutine(userID int, routine domain.Routine) error
-	Users(username string) ([]domain.User, error)
-	SaveUser(username string, email string, passwordHash string) error
-	SaveSession(userID int, sessionToken string, csrfToken string) error
-	GetUserSession(userID int) (domain.UserSession, error)
-	DeleteSession(userID int) error
+	ExerciseStorage
+	RoutineStorage
+	WorkoutStorage
+	UserStorage
+	SessionStorage
+	LoginAttemptStorage
+	AccessTokenStorage
+	PasswordResetStorage
+	EmailVerificationStorage
+	MFAStorage
+	DataExportStorage
+}
+
+// ExerciseStorage stores the exercise catalog and the exercises of the users.
+type ExerciseStorage interface {
+	Exercises(userID int, query domain.ExerciseQuery) ([]domain.Exercise, error)
+	Exercise(userID int, exerciseID int) (domain.Exercise, error)
+	SaveExercise(userID int, exercise domain.Exercise) (int, error)
+	UpdateExercise(userID int, exercise domain.Exercise) error
+	DeleteExercise(userID int, exerciseID int) error
+}
+
+// RoutineStorage stores the routines and who they are shared with.
+type RoutineStorage interface {
+	SaveRoutine(userID int, routine domain.Routine) (int, error)
+	UpdateRoutine(userID int, routine domain.Routine) error
+	DeleteRoutine(userID int, routineID int) error
 	Routines(userID int) ([]domain.Routine, error)
-	Routine(routineID int) (domain.Routine, error)
+	Routine(userID int, routineID int) (domain.Routine, error)
+	ShareRoutine(userID int, routineID int, sharedWithUserID int) error
+	UnshareRoutine(userID int, routineID int, sharedWithUserID int) error
+}
+
+// WorkoutStorage stores the workouts and their sets.
+type WorkoutStorage interface {
+	SaveWorkout(userID int, workout domain.Workout) (int, error)
+	SaveWorkoutSet(workoutID int, set domain.WorkoutSet) error
+	FinishWorkout(userID int, workoutID int, finishedAt time.Time) error
+	Workouts(userID int) ([]domain.Workout, error)
+	Workout(userID int, workoutID int) (domain.Workout, error)
+}
+
+// UserStorage stores the accounts.
+type UserStorage interface {
+	Users(username string) ([]domain.User, error)
+	User(userID int) (domain.User, error)
+	UserByEmail(email string) (domain.User, error)
+	SaveUser(username string, email string, passwordHash string) (int, error)
+	ChangePassword(userID int, passwordHash string, keepSessionID int, changedAt time.Time) error
+	ChangeEmail(userID int, email string) error
+	ScheduleUserDeletion(userID int, deleteAt *time.Time) error
+	DeleteScheduledUsers(now time.Time) (int, error)
+}
+
+// SessionStorage stores the logged in sessions.
+type SessionStorage interface {
+	SaveSession(session domain.UserSession) (int, error)
+	Session(sessionToken string) (domain.UserSession, error)
+	Sessions(userID int) ([]domain.UserSession, error)
+	TouchSession(sessionID int, lastSeenAt, expiresAt time.Time, ip string) error
+	DeleteSession(userID int, sessionID int) error
+	DeleteOtherSessions(userID int, sessionID int) error
+	DeleteExpiredSessions(now time.Time) (int, error)
+}
+
+// LoginAttemptStorage stores the login attempts the lockouts are computed from.
+type LoginAttemptStorage interface {
+	SaveLoginAttempt(attempt domain.LoginAttempt) error
+	LoginAttempts(username string, since time.Time, limit int) ([]domain.LoginAttempt, error)
+	LoginFailuresByIP(ip string, since time.Time, limit int) ([]domain.LoginAttempt, error)
+	DeleteLoginAttempts(before time.Time) (int, error)
+}
+
+// AccessTokenStorage stores the personal access tokens.
+type AccessTokenStorage interface {
+	SaveAccessToken(token domain.AccessToken) (int, error)
+	AccessToken(tokenHash string) (domain.AccessToken, error)
+	AccessTokens(userID int) ([]domain.AccessToken, error)
+	TouchAccessToken(tokenID int, lastUsedAt time.Time) error
+	DeleteAccessToken(userID int, tokenID int) error
+}
+
+// PasswordResetStorage stores the password reset tokens.
+type PasswordResetStorage interface {
+	SavePasswordReset(reset domain.PasswordReset) (int, error)
+	PasswordReset(tokenHash string) (domain.PasswordReset, error)
+	PasswordResets(userID int, since time.Time) ([]domain.PasswordReset, error)
+	ResetPassword(resetID int, passwordHash string, usedAt time.Time) error
+}
+
+// EmailVerificationStorage stores the email verification tokens.
+type EmailVerificationStorage interface {
+	SaveEmailVerification(verification domain.EmailVerification) (int, error)
+	EmailVerification(tokenHash string) (domain.EmailVerification, error)
+	EmailVerifications(userID int, since time.Time) ([]domain.EmailVerification, error)
+	VerifyEmail(verificationID int, verifiedAt time.Time) error
+}
+
+// MFAStorage stores the two-factor authentication secrets, recovery codes and login challenges.
+type MFAStorage interface {
+	TOTP(userID int) (domain.TOTP, error)
+	SaveTOTP(totp domain.TOTP) error
+	ConfirmTOTP(userID int, confirmedAt time.Time, step int64, recoveryCodeHashes []string) error
+	UseTOTPStep(userID int, step int64) error
+	UseRecoveryCode(userID int, codeHash string, usedAt time.Time) error
+	DeleteTOTP(userID int) error
+	SaveMFAChallenge(challenge domain.MFAChallenge) (int, error)
+	MFAChallenge(tokenHash string) (domain.MFAChallenge, error)
+	FailMFAChallenge(challengeID int) error
+	DeleteMFAChallenge(challengeID int) error
+}
+
+// DataExportStorage stores the data exports and their archives.
+type DataExportStorage interface {
+	SaveDataExport(export domain.DataExport) (int, error)
+	DataExport(userID int, exportID int) (domain.DataExport, error)
+	DataExports(userID int) ([]domain.DataExport, error)
+	ClaimDataExports(claimedAt, staleBefore time.Time) ([]domain.DataExport, error)
+	FinishDataExport(exportID int, status domain.DataExportStatus, archive []byte, completedAt, expiresAt time.Time) error
+	DataExportArchive(userID int, exportID int) ([]byte, error)
+	DeleteExpiredDataExports(now time.Time) (int, error)
+}
+
+// memoryURL selects the in-memory storage, which starts empty every time.
+const memoryURL = ":memory:"
+
+// Open returns the storage for a database URL: postgres:// and postgresql:// URLs use PostgreSQL,
+// ":memory:" keeps everything in memory and anything else is the path of a SQLite database.
+func Open(databaseURL string) (Storage, error) {
+	if databaseURL == memoryURL {
+		return NewMemoryStorage()
+	}
+	if isPostgresURL(databaseURL) {
+		return NewPostgresStorage(databaseURL)
+	}
+	return NewSqliteStorage(databaseURL)
+}
+
+// Migrate applies the pending migrations to the database of the URL and returns their names.
+func Migrate(databaseURL string) ([]string, error) {
+	if databaseURL == memoryURL {
+		return nil, nil
+	}
+	if isPostgresURL(databaseURL) {
+		return MigratePostgres(databaseURL)
+	}
+	return MigrateSqlite(databaseURL)
+}
+
+func isPostgresURL(databaseURL string) bool {
+	return strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://")
 }
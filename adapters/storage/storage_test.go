@@ -0,0 +1,1155 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"gymlog/domain"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testCatalog is a small exercise catalog so every conformance test seeds quickly.
+const testCatalog = `{"version": 1, "exercises": [
+	{"id": 1, "slug": "push-up", "name": "push-up", "target": "pectorals", "equipment": "bodyweight", "secondary_muscles": ["triceps"]},
+	{"id": 2, "slug": "barbell-squat", "name": "Barbell squat", "target": "quads", "equipment": "barbell", "secondary_muscles": ["glutes"]},
+	{"id": 3, "slug": "bulgarian-split-squat", "name": "bulgarian split squat", "target": "quads", "equipment": "dumbbell", "unilateral": true},
+	{"id": 4, "slug": "curl", "name": "curl", "target": "biceps", "equipment": "dumbbell"}
+]}`
+
+func TestSqliteStorage(t *testing.T) {
+	testStorage(t, func(t *testing.T) Storage {
+		return openSqlite(t, filepath.Join(t.TempDir(), "gymlog.db"))
+	})
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, func(t *testing.T) Storage {
+		store, err := NewMemoryStorage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	})
+}
+
+// TestPostgresStorage runs the conformance tests against GYMLOG_TEST_POSTGRES_URL, or a local server
+// when it is not set, giving every test its own schema. It is skipped when the server is unreachable.
+func TestPostgresStorage(t *testing.T) {
+	dsn := os.Getenv("GYMLOG_TEST_POSTGRES_URL")
+	if dsn == "" {
+		dsn = "postgres://postgres@localhost:5432/postgres?sslmode=disable"
+	}
+	admin, err := openPostgresDB(dsn)
+	if err != nil {
+		t.Skipf("PostgreSQL is not available at %s: %v", dsn, err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	var schemas atomic.Int64
+	testStorage(t, func(t *testing.T) Storage {
+		schema := fmt.Sprintf("gymlog_test_%d_%d", os.Getpid(), schemas.Add(1))
+		if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+		schemaDSN, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params := schemaDSN.Query()
+		params.Set("search_path", schema)
+		schemaDSN.RawQuery = params.Encode()
+
+		store, err := NewPostgresStorage(schemaDSN.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+// testStorage checks the behavior every Storage implementation must have.
+// open returns an empty storage seeded with testCatalog.
+func testStorage(t *testing.T, open func(t *testing.T) Storage) {
+	withCatalog(t, testCatalog)
+
+	tests := map[string]func(t *testing.T, store Storage){
+		"users and sessions": testUsersAndSessions,
+		"account changes":    testAccountChanges,
+		"account deletion":   testAccountDeletion,
+		"access tokens":      testAccessTokens,
+		"password resets":    testPasswordResets,
+		"email verification": testEmailVerification,
+		"two factor":         testTwoFactor,
+		"login attempts":     testLoginAttempts,
+		"data exports":       testDataExports,
+		"exercises":          testExercises,
+		"exercise queries":   testExerciseQueries,
+		"routines":           testRoutines,
+		"shared routines":    testSharedRoutines,
+		"workouts":           testWorkouts,
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, open(t))
+		})
+	}
+}
+
+func saveTestUser(t *testing.T, store Storage, username string) int {
+	t.Helper()
+
+	userID, err := store.SaveUser(username, username+"@gymlog.test", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := store.Users(username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].ID != userID {
+		t.Fatalf("got users %+v named %s, want the one with ID %d", users, username, userID)
+	}
+	return userID
+}
+
+func testUsersAndSessions(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	if _, err := store.SaveUser("alice", "other@gymlog.test", "hash"); !errors.Is(err, ErrUsernameTaken) {
+		t.Fatalf("same username: got %v, want ErrUsernameTaken", err)
+	}
+	if _, err := store.SaveUser("Alice", "other@gymlog.test", "hash"); !errors.Is(err, ErrUsernameTaken) {
+		t.Fatalf("same username in another case: got %v, want ErrUsernameTaken", err)
+	}
+	if _, err := store.SaveUser("other", "ALICE@gymlog.test", "hash"); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("same email in another case: got %v, want ErrEmailTaken", err)
+	}
+	if users, err := store.Users("ALICE"); err != nil || len(users) != 1 || users[0].ID != aliceID {
+		t.Fatalf("username in another case: got %v, %v", users, err)
+	}
+	if user, err := store.UserByEmail("Alice@GymLog.test"); err != nil || user.ID != aliceID {
+		t.Fatalf("email in another case: got %+v, %v", user, err)
+	}
+	if users, err := store.Users("nobody"); err != nil || len(users) != 0 {
+		t.Fatalf("unknown user: got %v, %v", users, err)
+	}
+	if user, err := store.User(aliceID); err != nil || user.Username != "alice" || user.Email != "alice@gymlog.test" {
+		t.Fatalf("user by ID: got %+v, %v", user, err)
+	}
+	if _, err := store.User(aliceID + 100); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown user ID: got %v, want sql.ErrNoRows", err)
+	}
+
+	bobID := saveTestUser(t, store, "bob")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var sessionIDs []int
+	for i, device := range []string{"phone", "laptop"} {
+		sessionID, err := store.SaveSession(domain.UserSession{
+			UserID:       aliceID,
+			SessionToken: "token-" + device,
+			CSRFToken:    "csrf-" + device,
+			DeviceName:   device,
+			UserAgent:    "test",
+			IP:           "10.0.0.1",
+			CreatedAt:    now.Add(time.Duration(i) * time.Minute),
+			LastSeenAt:   now.Add(time.Duration(i) * time.Minute),
+			ExpiresAt:    now.Add(time.Duration(i)*time.Minute + time.Hour),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	bobSessionID, err := store.SaveSession(domain.UserSession{UserID: bobID, SessionToken: "token-bob", CSRFToken: "csrf-bob", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SaveSession(domain.UserSession{UserID: bobID, SessionToken: "token-bob", CSRFToken: "x", CreatedAt: now, LastSeenAt: now}); err == nil {
+		t.Fatal("saved two sessions with the same token")
+	}
+
+	phone, err := store.Session("token-phone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if phone.ID != sessionIDs[0] || phone.UserID != aliceID || phone.CSRFToken != "csrf-phone" || phone.DeviceName != "phone" || !phone.CreatedAt.Equal(now) || !phone.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("got session %+v", phone)
+	}
+	if _, err := store.Session("nope"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown token: got %v, want sql.ErrNoRows", err)
+	}
+
+	// Using the phone again makes it the most recently used session and extends it.
+	if err := store.TouchSession(phone.ID, now.Add(time.Hour), now.Add(2*time.Hour), "10.0.0.2"); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := store.Sessions(aliceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].DeviceName != "phone" || sessions[0].IP != "10.0.0.2" || !sessions[0].LastSeenAt.Equal(now.Add(time.Hour)) || !sessions[0].ExpiresAt.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("got sessions %+v", sessions)
+	}
+
+	// Only the laptop and bob's session are expired after an hour and a half.
+	deleted, err := store.DeleteExpiredSessions(now.Add(90 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Fatalf("deleted %d expired sessions, want 2", deleted)
+	}
+	if _, err := store.Session("token-bob"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expired session is still there: %v", err)
+	}
+	if _, err := store.Session("token-phone"); err != nil {
+		t.Fatalf("extended session was deleted: %v", err)
+	}
+	bobSessionID, err = store.SaveSession(domain.UserSession{UserID: bobID, SessionToken: "token-bob", CSRFToken: "csrf-bob", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(3 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	laptopID, err := store.SaveSession(domain.UserSession{UserID: aliceID, SessionToken: "token-laptop", CSRFToken: "csrf-laptop", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(3 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionIDs[1] = laptopID
+
+	if err := store.DeleteSession(bobID, phone.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob deletes alice's session: %v", err)
+	}
+	if err := store.DeleteOtherSessions(aliceID, sessionIDs[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Session("token-phone"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("other session is still there: %v", err)
+	}
+	if _, err := store.Session("token-bob"); err != nil {
+		t.Fatalf("bob's session was deleted: %v", err)
+	}
+	if err := store.DeleteSession(aliceID, sessionIDs[1]); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, err := store.Sessions(aliceID); err != nil || len(sessions) != 0 {
+		t.Fatalf("got %v, %v after logging out everywhere", sessions, err)
+	}
+	if err := store.DeleteSession(bobID, bobSessionID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testAccountChanges(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var sessionIDs []int
+	for _, token := range []string{"phone", "laptop"} {
+		sessionID, err := store.SaveSession(domain.UserSession{UserID: aliceID, SessionToken: token, CSRFToken: token, CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	for _, token := range []domain.AccessToken{
+		{UserID: aliceID, Name: "cron", Scope: domain.ScopeWrite, TokenHash: "token-hash-alice", CreatedAt: now},
+		{UserID: bobID, Name: "cron", Scope: domain.ScopeWrite, TokenHash: "token-hash-bob", CreatedAt: now},
+	} {
+		if _, err := store.SaveAccessToken(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resetID, err := store.SavePasswordReset(domain.PasswordReset{UserID: aliceID, TokenHash: "reset", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.ChangePassword(aliceID, "new-hash", sessionIDs[0], now); err != nil {
+		t.Fatal(err)
+	}
+	if user, err := store.User(aliceID); err != nil || user.PasswordHash != "new-hash" {
+		t.Fatalf("changed password: got %+v, %v", user, err)
+	}
+	if sessions, err := store.Sessions(aliceID); err != nil || len(sessions) != 1 || sessions[0].ID != sessionIDs[0] {
+		t.Fatalf("sessions after changing the password: got %+v, %v", sessions, err)
+	}
+	if reset, err := store.PasswordReset("reset"); err != nil || reset.ID != resetID || reset.UsedAt == nil {
+		t.Fatalf("password reset after changing the password: got %+v, %v", reset, err)
+	}
+	if _, err := store.AccessToken("token-hash-alice"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("access token survived the password change: %v", err)
+	}
+	if _, err := store.AccessToken("token-hash-bob"); err != nil {
+		t.Fatalf("password change revoked the access token of another user: %v", err)
+	}
+	if err := store.ChangePassword(aliceID+100, "new-hash", 0, now); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown user: got %v, want sql.ErrNoRows", err)
+	}
+
+	if _, err := store.SaveEmailVerification(domain.EmailVerification{UserID: aliceID, Email: "alice@gymlog.test", TokenHash: "verify", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	verification, err := store.EmailVerification("verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.VerifyEmail(verification.ID, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ChangeEmail(aliceID, "Bob@gymlog.test"); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("email of another user: got %v, want ErrEmailTaken", err)
+	}
+	if err := store.ChangeEmail(aliceID, "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if user, err := store.User(aliceID); err != nil || user.Email != "alice@example.com" || user.EmailVerified() {
+		t.Fatalf("changed email: got %+v, %v", user, err)
+	}
+	if err := store.ChangeEmail(aliceID+100, "nobody@gymlog.test"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown user: got %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testAccountDeletion(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	exercise, err := domain.CreateExercise(domain.Exercise{Name: "landmine press", Target: "delts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseID, err := store.SaveExercise(aliceID, exercise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SaveRoutine(aliceID, domain.Routine{Name: "push", Exercises: []domain.ExerciseDetail{{ID: exerciseID}}}); err != nil {
+		t.Fatal(err)
+	}
+	routines, err := store.Routines(aliceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routineID := routines[0].ID
+	if err := store.ShareRoutine(aliceID, routineID, bobID); err != nil {
+		t.Fatal(err)
+	}
+	aliceWorkoutID, err := store.SaveWorkout(aliceID, domain.StartWorkout(&routineID, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveWorkoutSet(aliceWorkoutID, domain.WorkoutSet{ExerciseID: exerciseID, SetIndex: 1, Reps: 8}); err != nil {
+		t.Fatal(err)
+	}
+	bobWorkoutID, err := store.SaveWorkout(bobID, domain.StartWorkout(&routineID, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SaveSession(domain.UserSession{UserID: aliceID, SessionToken: "alice", CSRFToken: "alice", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	deleteAt := now.Add(time.Hour)
+	if err := store.ScheduleUserDeletion(aliceID, &deleteAt); err != nil {
+		t.Fatal(err)
+	}
+	if user, err := store.User(aliceID); err != nil || user.DeletionScheduledAt == nil || !user.DeletionScheduledAt.Equal(deleteAt) {
+		t.Fatalf("scheduled deletion: got %+v, %v", user, err)
+	}
+	if deleted, err := store.DeleteScheduledUsers(deleteAt.Add(-time.Second)); err != nil || deleted != 0 {
+		t.Fatalf("before the deletion time: deleted %d, %v", deleted, err)
+	}
+	if err := store.ScheduleUserDeletion(aliceID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := store.DeleteScheduledUsers(deleteAt); err != nil || deleted != 0 {
+		t.Fatalf("cancelled deletion: deleted %d, %v", deleted, err)
+	}
+	if err := store.ScheduleUserDeletion(aliceID+100, &deleteAt); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown user: got %v, want sql.ErrNoRows", err)
+	}
+
+	if err := store.ScheduleUserDeletion(aliceID, &deleteAt); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := store.DeleteScheduledUsers(deleteAt); err != nil || deleted != 1 {
+		t.Fatalf("deleted %d users, %v", deleted, err)
+	}
+	if _, err := store.User(aliceID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("deleted user: got %v, want sql.ErrNoRows", err)
+	}
+	if _, err := store.Session("alice"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("session of the deleted user: got %v, want sql.ErrNoRows", err)
+	}
+	if _, err := store.Exercise(aliceID, exerciseID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("exercise of the deleted user: got %v, want sql.ErrNoRows", err)
+	}
+	if _, err := store.Routine(bobID, routineID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("routine shared by the deleted user: got %v, want sql.ErrNoRows", err)
+	}
+	if workouts, err := store.Workouts(aliceID); err != nil || len(workouts) != 0 {
+		t.Fatalf("workouts of the deleted user: got %+v, %v", workouts, err)
+	}
+	if workout, err := store.Workout(bobID, bobWorkoutID); err != nil || workout.RoutineID != nil {
+		t.Fatalf("workout from the deleted routine: got %+v, %v", workout, err)
+	}
+	if _, err := store.User(bobID); err != nil {
+		t.Fatalf("other user: %v", err)
+	}
+}
+
+func testAccessTokens(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(24 * time.Hour)
+	cronID, err := store.SaveAccessToken(domain.AccessToken{UserID: aliceID, Name: "cron", Scope: domain.ScopeRead, TokenHash: "hash-cron", CreatedAt: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	appID, err := store.SaveAccessToken(domain.AccessToken{UserID: aliceID, Name: "app", Scope: domain.ScopeWrite, TokenHash: "hash-app", CreatedAt: now.Add(time.Minute), ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SaveAccessToken(domain.AccessToken{UserID: bobID, Name: "copy", Scope: domain.ScopeRead, TokenHash: "hash-app", CreatedAt: now}); err == nil {
+		t.Fatal("saved two access tokens with the same hash")
+	}
+
+	app, err := store.AccessToken("hash-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.ID != appID || app.UserID != aliceID || app.Name != "app" || app.Scope != domain.ScopeWrite ||
+		app.ExpiresAt == nil || !app.ExpiresAt.Equal(expiresAt) || app.LastUsedAt != nil {
+		t.Fatalf("got access token %+v", app)
+	}
+	if _, err := store.AccessToken("nope"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown hash: got %v, want sql.ErrNoRows", err)
+	}
+
+	if err := store.TouchAccessToken(cronID, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := store.AccessTokens(aliceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 2 || tokens[0].Name != "app" || tokens[1].ExpiresAt != nil ||
+		tokens[1].LastUsedAt == nil || !tokens[1].LastUsedAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("got access tokens %+v", tokens)
+	}
+
+	if err := store.DeleteAccessToken(bobID, cronID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob deletes alice's token: %v", err)
+	}
+	if err := store.DeleteAccessToken(aliceID, cronID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AccessToken("hash-cron"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("deleted token is still there: %v", err)
+	}
+}
+
+func testPasswordResets(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+	if user, err := store.UserByEmail("alice@gymlog.test"); err != nil || user.ID != aliceID {
+		t.Fatalf("user by email: got %+v, %v", user, err)
+	}
+	if _, err := store.UserByEmail("nobody@gymlog.test"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown email: got %v, want sql.ErrNoRows", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, session := range []domain.UserSession{
+		{UserID: aliceID, SessionToken: "token-alice", CSRFToken: "csrf", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)},
+		{UserID: bobID, SessionToken: "token-bob", CSRFToken: "csrf", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)},
+	} {
+		if _, err := store.SaveSession(session); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, token := range []domain.AccessToken{
+		{UserID: aliceID, Name: "cron", Scope: domain.ScopeWrite, TokenHash: "token-hash-alice", CreatedAt: now},
+		{UserID: bobID, Name: "cron", Scope: domain.ScopeWrite, TokenHash: "token-hash-bob", CreatedAt: now},
+	} {
+		if _, err := store.SaveAccessToken(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var resetIDs []int
+	for i, hash := range []string{"hash-1", "hash-2"} {
+		createdAt := now.Add(time.Duration(i) * time.Minute)
+		resetID, err := store.SavePasswordReset(domain.PasswordReset{UserID: aliceID, TokenHash: hash, CreatedAt: createdAt, ExpiresAt: now.Add(time.Hour)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resetIDs = append(resetIDs, resetID)
+	}
+
+	resets, err := store.PasswordResets(aliceID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resets) != 2 || resets[0].ID != resetIDs[1] || resets[1].ID != resetIDs[0] {
+		t.Fatalf("got password resets %+v, want the newest first", resets)
+	}
+	if resets, err := store.PasswordResets(aliceID, now.Add(time.Minute)); err != nil || len(resets) != 1 {
+		t.Fatalf("resets since a minute later: got %+v, %v", resets, err)
+	}
+	if resets, err := store.PasswordResets(bobID, now); err != nil || len(resets) != 0 {
+		t.Fatalf("bob's resets: got %+v, %v", resets, err)
+	}
+
+	reset, err := store.PasswordReset("hash-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reset.ID != resetIDs[0] || reset.UserID != aliceID || !reset.ExpiresAt.Equal(now.Add(time.Hour)) || reset.UsedAt != nil {
+		t.Fatalf("got password reset %+v", reset)
+	}
+	if _, err := store.PasswordReset("nope"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown hash: got %v, want sql.ErrNoRows", err)
+	}
+
+	if err := store.ResetPassword(reset.ID, "new-hash", now); err != nil {
+		t.Fatal(err)
+	}
+	if user, err := store.User(aliceID); err != nil || user.PasswordHash != "new-hash" {
+		t.Fatalf("password was not changed: %+v, %v", user, err)
+	}
+	if _, err := store.Session("token-alice"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("session survived the reset: %v", err)
+	}
+	if _, err := store.Session("token-bob"); err != nil {
+		t.Fatalf("reset logged out another user: %v", err)
+	}
+	if _, err := store.AccessToken("token-hash-alice"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("access token survived the reset: %v", err)
+	}
+	if _, err := store.AccessToken("token-hash-bob"); err != nil {
+		t.Fatalf("reset revoked the access token of another user: %v", err)
+	}
+	if err := store.ResetPassword(reset.ID, "again", now); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("reused reset: got %v, want sql.ErrNoRows", err)
+	}
+	if other, err := store.PasswordReset("hash-2"); err != nil || other.UsedAt == nil {
+		t.Fatalf("other reset is still usable: %+v, %v", other, err)
+	}
+}
+
+func testEmailVerification(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	if user, err := store.User(aliceID); err != nil || user.EmailVerified() {
+		t.Fatalf("new user is verified: %+v, %v", user, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, hash := range []string{"hash-1", "hash-2"} {
+		createdAt := now.Add(time.Duration(i) * time.Minute)
+		_, err := store.SaveEmailVerification(domain.EmailVerification{
+			UserID: aliceID, Email: "alice@gymlog.test", TokenHash: hash, CreatedAt: createdAt, ExpiresAt: createdAt.Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recent, err := store.EmailVerifications(aliceID, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 1 || recent[0].TokenHash != "hash-2" {
+		t.Fatalf("got recent verifications %+v", recent)
+	}
+	all, err := store.EmailVerifications(aliceID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].TokenHash != "hash-2" || all[1].TokenHash != "hash-1" {
+		t.Fatalf("got verifications %+v, want the newest first", all)
+	}
+
+	verification, err := store.EmailVerification("hash-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.UserID != aliceID || verification.Email != "alice@gymlog.test" || verification.UsedAt != nil {
+		t.Fatalf("got email verification %+v", verification)
+	}
+	if _, err := store.EmailVerification("nope"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown hash: got %v, want sql.ErrNoRows", err)
+	}
+
+	if err := store.VerifyEmail(verification.ID, now); err != nil {
+		t.Fatal(err)
+	}
+	user, err := store.User(aliceID)
+	if err != nil || !user.EmailVerified() || !user.EmailVerifiedAt.Equal(now) {
+		t.Fatalf("email was not verified: %+v, %v", user, err)
+	}
+	if err := store.VerifyEmail(verification.ID, now); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("reused verification: got %v, want sql.ErrNoRows", err)
+	}
+	if other, err := store.EmailVerification("hash-2"); err != nil || other.UsedAt == nil {
+		t.Fatalf("other verification is still usable: %+v, %v", other, err)
+	}
+}
+
+func testTwoFactor(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	if _, err := store.TOTP(aliceID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("TOTP of a new user: got %v, want sql.ErrNoRows", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, secret := range []string{"FIRST", "SECOND"} {
+		if err := store.SaveTOTP(domain.TOTP{UserID: aliceID, Secret: secret, CreatedAt: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	totp, err := store.TOTP(aliceID)
+	if err != nil || totp.Secret != "SECOND" || totp.Enabled() {
+		t.Fatalf("unconfirmed enrollment was not replaced: %+v, %v", totp, err)
+	}
+	if err := store.UseTOTPStep(aliceID, 10); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("code of an unconfirmed app: got %v, want sql.ErrNoRows", err)
+	}
+
+	if err := store.ConfirmTOTP(aliceID, now, 10, []string{"code-1", "code-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ConfirmTOTP(aliceID, now, 11, nil); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("confirm twice: got %v, want sql.ErrNoRows", err)
+	}
+	if err := store.SaveTOTP(domain.TOTP{UserID: aliceID, Secret: "THIRD", CreatedAt: now}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("enroll over a confirmed app: got %v, want sql.ErrNoRows", err)
+	}
+	if totp, err := store.TOTP(aliceID); err != nil || !totp.Enabled() || totp.Secret != "SECOND" || totp.LastStep != 10 {
+		t.Fatalf("got confirmed TOTP %+v, %v", totp, err)
+	}
+
+	if err := store.UseTOTPStep(aliceID, 10); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("replayed step: got %v, want sql.ErrNoRows", err)
+	}
+	if err := store.UseTOTPStep(aliceID, 11); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UseRecoveryCode(aliceID, "code-1", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UseRecoveryCode(aliceID, "code-1", now); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("reused recovery code: got %v, want sql.ErrNoRows", err)
+	}
+
+	challengeID, err := store.SaveMFAChallenge(domain.MFAChallenge{UserID: aliceID, TokenHash: "challenge", CreatedAt: now, ExpiresAt: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.FailMFAChallenge(challengeID); err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := store.MFAChallenge("challenge")
+	if err != nil || challenge.ID != challengeID || challenge.UserID != aliceID || challenge.Attempts != 1 {
+		t.Fatalf("got MFA challenge %+v, %v", challenge, err)
+	}
+	if err := store.DeleteMFAChallenge(challengeID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteMFAChallenge(challengeID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("delete challenge twice: got %v, want sql.ErrNoRows", err)
+	}
+
+	for _, hash := range []string{"replaced", "other"} {
+		if _, err := store.SaveMFAChallenge(domain.MFAChallenge{UserID: aliceID, TokenHash: hash, CreatedAt: now, ExpiresAt: now.Add(time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.MFAChallenge("replaced"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("a new login kept the pending one: got %v, want sql.ErrNoRows", err)
+	}
+	if err := store.DeleteTOTP(aliceID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.TOTP(aliceID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("TOTP after disabling: got %v, want sql.ErrNoRows", err)
+	}
+	if err := store.UseRecoveryCode(aliceID, "code-2", now); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("recovery code after disabling: got %v, want sql.ErrNoRows", err)
+	}
+	if _, err := store.MFAChallenge("other"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("MFA challenge after disabling: got %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testLoginAttempts(t *testing.T, store Storage) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, attempt := range []domain.LoginAttempt{
+		{Username: "alice", IP: "10.0.0.1", Outcome: domain.LoginFailed},
+		{Username: "alice", IP: "10.0.0.1", Outcome: domain.LoginSucceeded},
+		{Username: "alice", IP: "10.0.0.2", Outcome: domain.LoginFailed},
+		{Username: "alice", IP: "10.0.0.2", Outcome: domain.LoginLocked},
+		{Username: "bob", IP: "10.0.0.2", Outcome: domain.LoginFailed},
+	} {
+		attempt.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		if err := store.SaveLoginAttempt(attempt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	attempts, err := store.LoginAttempts("alice", now, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 3 || attempts[0].Outcome != domain.LoginFailed || attempts[0].IP != "10.0.0.2" || attempts[1].Outcome != domain.LoginSucceeded {
+		t.Fatalf("got attempts %+v, want the newest first without the locked ones", attempts)
+	}
+	if !attempts[0].CreatedAt.Equal(now.Add(2 * time.Minute)) {
+		t.Fatalf("got attempt time %v", attempts[0].CreatedAt)
+	}
+	if attempts, err := store.LoginAttempts("alice", now.Add(time.Minute), 1); err != nil || len(attempts) != 1 || attempts[0].Outcome != domain.LoginFailed {
+		t.Fatalf("limited attempts: got %+v, %v", attempts, err)
+	}
+
+	failures, err := store.LoginFailuresByIP("10.0.0.2", now, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failures) != 2 || failures[0].Username != "bob" || failures[1].Username != "alice" {
+		t.Fatalf("got failures by IP %+v", failures)
+	}
+
+	deleted, err := store.DeleteLoginAttempts(now.Add(2 * time.Minute))
+	if err != nil || deleted != 2 {
+		t.Fatalf("deleted %d attempts, %v, want 2", deleted, err)
+	}
+	if attempts, err := store.LoginAttempts("alice", now, 10); err != nil || len(attempts) != 1 {
+		t.Fatalf("attempts after deleting the old ones: got %+v, %v", attempts, err)
+	}
+}
+
+func testDataExports(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var exportIDs []int
+	for i := range 2 {
+		exportID, err := store.SaveDataExport(domain.DataExport{UserID: aliceID, Status: domain.DataExportPending, CreatedAt: now.Add(time.Duration(i) * time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exportIDs = append(exportIDs, exportID)
+	}
+	if _, err := store.DataExportArchive(aliceID, exportIDs[0]); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("archive of a pending export: got %v, want sql.ErrNoRows", err)
+	}
+	if err := store.FinishDataExport(exportIDs[0], domain.DataExportReady, nil, now, now.Add(time.Hour)); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("finishing an unclaimed export: got %v, want sql.ErrNoRows", err)
+	}
+
+	staleBefore := now.Add(-30 * time.Minute)
+	claimed, err := store.ClaimDataExports(now, staleBefore)
+	if err != nil || len(claimed) != 2 || claimed[0].ID != exportIDs[0] || claimed[1].Status != domain.DataExportBuilding {
+		t.Fatalf("claimed exports: got %+v, %v", claimed, err)
+	}
+	if claimed, err := store.ClaimDataExports(now.Add(time.Minute), staleBefore); err != nil || len(claimed) != 0 {
+		t.Fatalf("claiming twice: got %+v, %v", claimed, err)
+	}
+	// A builder that died leaves its exports building, they are claimed again once stale.
+	if claimed, err := store.ClaimDataExports(now.Add(time.Hour), now.Add(time.Minute)); err != nil || len(claimed) != 2 {
+		t.Fatalf("claiming stale exports: got %+v, %v", claimed, err)
+	}
+
+	archive := []byte("PK\x03\x04 zip")
+	if err := store.FinishDataExport(exportIDs[0], domain.DataExportReady, archive, now, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.FinishDataExport(exportIDs[0], domain.DataExportFailed, nil, now, now.Add(time.Hour)); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("finishing twice: got %v, want sql.ErrNoRows", err)
+	}
+	if err := store.FinishDataExport(exportIDs[1], domain.DataExportFailed, nil, now, now.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if claimed, err := store.ClaimDataExports(now.Add(2*time.Hour), now.Add(2*time.Hour)); err != nil || len(claimed) != 0 {
+		t.Fatalf("claiming finished exports: got %+v, %v", claimed, err)
+	}
+
+	export, err := store.DataExport(aliceID, exportIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.Status != domain.DataExportReady || export.CompletedAt == nil || !export.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("got %+v", export)
+	}
+	if got, err := store.DataExportArchive(aliceID, exportIDs[0]); err != nil || string(got) != string(archive) {
+		t.Fatalf("archive: got %q, %v", got, err)
+	}
+	if _, err := store.DataExport(bobID, exportIDs[0]); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob reads alice's export: got %v, want sql.ErrNoRows", err)
+	}
+	if _, err := store.DataExportArchive(bobID, exportIDs[0]); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob downloads alice's export: got %v, want sql.ErrNoRows", err)
+	}
+	if exports, err := store.DataExports(aliceID); err != nil || len(exports) != 2 || exports[0].ID != exportIDs[1] {
+		t.Fatalf("exports: got %+v, %v", exports, err)
+	}
+
+	if deleted, err := store.DeleteExpiredDataExports(now.Add(time.Hour)); err != nil || deleted != 1 {
+		t.Fatalf("deleted %d expired exports, %v", deleted, err)
+	}
+	if exports, err := store.DataExports(aliceID); err != nil || len(exports) != 1 || exports[0].ID != exportIDs[1] {
+		t.Fatalf("exports after deleting the expired ones: got %+v, %v", exports, err)
+	}
+}
+
+func testExercises(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+
+	pushUp, err := store.Exercise(bobID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pushUp.Slug != "push-up" || pushUp.OwnerID != nil || len(pushUp.PrimaryMuscles) != 1 || pushUp.SecondaryMuscles[0] != "triceps" {
+		t.Fatalf("catalog exercise was not seeded: %+v", pushUp)
+	}
+
+	exercise, err := domain.CreateExercise(domain.Exercise{Name: "landmine press", Target: "delts", Equipment: "barbell", SecondaryMuscles: []string{"triceps"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseID, err := store.SaveExercise(aliceID, exercise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exerciseID <= 4 {
+		t.Fatalf("custom exercise got ID %d, which belongs to the catalog", exerciseID)
+	}
+	saved, err := store.Exercise(aliceID, exerciseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.OwnerID == nil || *saved.OwnerID != aliceID || saved.Equipment != "barbell" || len(saved.SecondaryMuscles) != 1 {
+		t.Fatalf("got %+v", saved)
+	}
+	if _, err := store.Exercise(bobID, exerciseID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob reads alice's exercise: %v", err)
+	}
+
+	exercise.ID = exerciseID
+	exercise.Name = "single arm landmine press"
+	exercise.SecondaryMuscles = nil
+	if err := store.UpdateExercise(bobID, exercise); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob updates alice's exercise: %v", err)
+	}
+	if err := store.UpdateExercise(aliceID, exercise); err != nil {
+		t.Fatal(err)
+	}
+	saved, err = store.Exercise(aliceID, exerciseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Name != "single arm landmine press" || len(saved.SecondaryMuscles) != 0 {
+		t.Fatalf("exercise was not updated: %+v", saved)
+	}
+	pushUp.ID = 1
+	if err := store.UpdateExercise(aliceID, pushUp); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("alice updates a catalog exercise: %v", err)
+	}
+
+	routine := domain.Routine{Name: "push", Exercises: []domain.ExerciseDetail{{ID: exerciseID}}}
+	if _, err := store.SaveRoutine(aliceID, routine); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteExercise(aliceID, exerciseID); !errors.Is(err, ErrExerciseInUse) {
+		t.Fatalf("deleting an exercise in use: got %v", err)
+	}
+	if err := store.DeleteExercise(bobID, exerciseID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob deletes alice's exercise: %v", err)
+	}
+	if err := store.DeleteExercise(aliceID, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("alice deletes a catalog exercise: %v", err)
+	}
+
+	unusedID, err := store.SaveExercise(aliceID, exercise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteExercise(aliceID, unusedID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Exercise(aliceID, unusedID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("deleted exercise is still there: %v", err)
+	}
+}
+
+func testExerciseQueries(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+	if _, err := store.SaveExercise(aliceID, domain.Exercise{Name: "Zercher squat", Target: "quads", Equipment: "barbell", PrimaryMuscles: []string{"quads"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	unilateral := true
+	tests := []struct {
+		name   string
+		userID int
+		query  domain.ExerciseQuery
+		want   []string
+	}{
+		{"all sorted by name", aliceID, domain.ExerciseQuery{}, []string{"Barbell squat", "bulgarian split squat", "curl", "push-up", "Zercher squat"}},
+		{"only own custom exercises", bobID, domain.ExerciseQuery{Sort: domain.SortByNameDesc}, []string{"push-up", "curl", "bulgarian split squat", "Barbell squat"}},
+		{"target", aliceID, domain.ExerciseQuery{ExerciseFilter: domain.ExerciseFilter{Target: "quads"}, Sort: domain.SortByID}, []string{"Barbell squat", "bulgarian split squat", "Zercher squat"}},
+		{"muscle", aliceID, domain.ExerciseQuery{ExerciseFilter: domain.ExerciseFilter{Muscle: "glutes"}}, []string{"Barbell squat"}},
+		{"equipment and unilateral", aliceID, domain.ExerciseQuery{ExerciseFilter: domain.ExerciseFilter{Equipment: "dumbbell", Unilateral: &unilateral}}, []string{"bulgarian split squat"}},
+		{"search", aliceID, domain.ExerciseQuery{ExerciseFilter: domain.ExerciseFilter{Search: "SQU"}}, []string{"Barbell squat", "bulgarian split squat", "Zercher squat"}},
+		{"search every term", aliceID, domain.ExerciseQuery{ExerciseFilter: domain.ExerciseFilter{Search: "split squ"}}, []string{"bulgarian split squat"}},
+		{"search escapes wildcards", aliceID, domain.ExerciseQuery{ExerciseFilter: domain.ExerciseFilter{Search: "%"}}, nil},
+		{"target then name", aliceID, domain.ExerciseQuery{Sort: domain.SortByTargetDesc}, []string{"Zercher squat", "bulgarian split squat", "Barbell squat", "push-up", "curl"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exercises, err := store.Exercises(tt.userID, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := exerciseNames(exercises); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Walking the pages with the cursor returns every exercise once, in order.
+	for _, sort := range []domain.ExerciseSort{domain.SortByName, domain.SortByTargetDesc, domain.SortByIDDesc} {
+		all, err := store.Exercises(aliceID, domain.ExerciseQuery{Sort: sort})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var paged []domain.Exercise
+		query := domain.ExerciseQuery{Sort: sort, Limit: 2}
+		for {
+			page, err := store.Exercises(aliceID, query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			paged = append(paged, page...)
+			if len(page) < query.Limit {
+				break
+			}
+			cursor := query.CursorAfter(page[len(page)-1])
+			query.After = &cursor
+		}
+		if fmt.Sprint(exerciseNames(paged)) != fmt.Sprint(exerciseNames(all)) {
+			t.Fatalf("sort %s: paged %v, want %v", sort, exerciseNames(paged), exerciseNames(all))
+		}
+	}
+}
+
+func exerciseNames(exercises []domain.Exercise) []string {
+	var names []string
+	for _, exercise := range exercises {
+		names = append(names, exercise.Name)
+	}
+	return names
+}
+
+func testRoutines(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+
+	var routineIDs []int
+	for _, name := range []string{"push", "legs"} {
+		routine := domain.Routine{Name: name, Exercises: []domain.ExerciseDetail{{ID: 1, Sets: 3, Reps: 10}, {ID: 2}}}
+		routineID, err := store.SaveRoutine(aliceID, routine)
+		if err != nil {
+			t.Fatal(err)
+		}
+		routineIDs = append(routineIDs, routineID)
+	}
+	routines, err := store.Routines(aliceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routines) != 2 || routines[0].Name != "push" || routines[1].Name != "legs" {
+		t.Fatalf("got routines %+v", routines)
+	}
+	if routines[0].ID != routineIDs[0] || routines[1].ID != routineIDs[1] || routines[0].UserID != aliceID {
+		t.Fatalf("got routines %+v, saved with IDs %v", routines, routineIDs)
+	}
+	if routines, err := store.Routines(bobID); err != nil || len(routines) != 0 {
+		t.Fatalf("bob's routines: got %v, %v", routines, err)
+	}
+
+	push := routines[0]
+	if len(push.Exercises) != 2 || push.Exercises[0] != (domain.ExerciseDetail{ID: 1, Sets: 3, Reps: 10}) {
+		t.Fatalf("got exercises %+v", push.Exercises)
+	}
+	if _, err := store.Routine(bobID, push.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob reads alice's routine: %v", err)
+	}
+
+	push.Name = "push day"
+	push.Description = "chest and triceps"
+	// The same exercise can appear more than once, like a finisher.
+	push.Exercises = []domain.ExerciseDetail{{ID: 4}, {ID: 1, Sets: 5, Reps: 5}, {ID: 4, Sets: 2, Reps: 20}}
+	if err := store.UpdateRoutine(bobID, push); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob updates alice's routine: %v", err)
+	}
+	if err := store.UpdateRoutine(aliceID, push); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := store.Routine(aliceID, push.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "push day" || updated.Description != "chest and triceps" || fmt.Sprint(updated.Exercises) != fmt.Sprint(push.Exercises) {
+		t.Fatalf("got %+v, want %+v", updated, push)
+	}
+
+	workoutID, err := store.SaveWorkout(aliceID, domain.StartWorkout(&push.ID, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteRoutine(bobID, push.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob deletes alice's routine: %v", err)
+	}
+	if err := store.DeleteRoutine(aliceID, push.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Routine(aliceID, push.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("deleted routine is still there: %v", err)
+	}
+	workout, err := store.Workout(aliceID, workoutID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workout.RoutineID != nil {
+		t.Fatalf("workout still points to the deleted routine %d", *workout.RoutineID)
+	}
+}
+
+func testSharedRoutines(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+	if _, err := store.SaveRoutine(aliceID, domain.Routine{Name: "push", Exercises: []domain.ExerciseDetail{{ID: 1}}}); err != nil {
+		t.Fatal(err)
+	}
+	routines, err := store.Routines(aliceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routineID := routines[0].ID
+
+	if err := store.ShareRoutine(bobID, routineID, bobID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob shares alice's routine: %v", err)
+	}
+	for range 2 {
+		if err := store.ShareRoutine(aliceID, routineID, bobID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shared, err := store.Routine(bobID, routineID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shared.Name != "push" || shared.UserID != aliceID || len(shared.Exercises) != 1 {
+		t.Fatalf("got %+v", shared)
+	}
+	if routines, err := store.Routines(bobID); err != nil || len(routines) != 0 {
+		t.Fatalf("shared routines are listed as bob's own: %v, %v", routines, err)
+	}
+	if err := store.UpdateRoutine(bobID, shared); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob updates a routine shared with him: %v", err)
+	}
+
+	if err := store.UnshareRoutine(bobID, routineID, bobID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob unshares alice's routine: %v", err)
+	}
+	if err := store.UnshareRoutine(aliceID, routineID, bobID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UnshareRoutine(aliceID, routineID, bobID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unsharing twice: %v", err)
+	}
+	if _, err := store.Routine(bobID, routineID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob reads an unshared routine: %v", err)
+	}
+}
+
+func testWorkouts(t *testing.T, store Storage) {
+	aliceID := saveTestUser(t, store, "alice")
+	bobID := saveTestUser(t, store, "bob")
+
+	start := time.Now().UTC().Truncate(time.Second)
+	firstID, err := store.SaveWorkout(aliceID, domain.StartWorkout(nil, start.Add(-time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondID, err := store.SaveWorkout(aliceID, domain.StartWorkout(nil, start))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sets := []domain.WorkoutSet{
+		{ExerciseID: 2, SetIndex: 1, Reps: 5, Weight: 100},
+		{ExerciseID: 2, SetIndex: 2, Reps: 5, Weight: 102.5},
+		{ExerciseID: 2, SetIndex: 1, Reps: 6, Weight: 100, Completed: true}, // Replaces the first set
+	}
+	for _, set := range sets {
+		if err := store.SaveWorkoutSet(secondID, set); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.FinishWorkout(bobID, secondID, start.Add(time.Hour)); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob finishes alice's workout: %v", err)
+	}
+	if err := store.FinishWorkout(aliceID, secondID, start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.FinishWorkout(aliceID, secondID, start.Add(2*time.Hour)); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("finishing twice: %v", err)
+	}
+	if err := store.SaveWorkoutSet(secondID, domain.WorkoutSet{ExerciseID: 2, SetIndex: 3, Reps: 5}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("set after finishing: got %v, want sql.ErrNoRows", err)
+	}
+	if err := store.SaveWorkoutSet(secondID+100, domain.WorkoutSet{ExerciseID: 2, SetIndex: 1, Reps: 5}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("set of an unknown workout: got %v, want sql.ErrNoRows", err)
+	}
+
+	workout, err := store.Workout(aliceID, secondID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !workout.StartedAt.Equal(start) || workout.FinishedAt == nil || !workout.FinishedAt.Equal(start.Add(time.Hour)) {
+		t.Fatalf("got times %v - %v", workout.StartedAt, workout.FinishedAt)
+	}
+	wantSets := []domain.WorkoutSet{sets[2], sets[1]}
+	if fmt.Sprint(workout.Sets) != fmt.Sprint(wantSets) {
+		t.Fatalf("got sets %+v, want %+v", workout.Sets, wantSets)
+	}
+	if _, err := store.Workout(bobID, secondID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("bob reads alice's workout: %v", err)
+	}
+
+	workouts, err := store.Workouts(aliceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workouts) != 2 || workouts[0].ID != secondID || workouts[1].ID != firstID || len(workouts[1].Sets) != 0 {
+		t.Fatalf("got workouts %+v", workouts)
+	}
+	if workouts, err := store.Workouts(bobID); err != nil || len(workouts) != 0 {
+		t.Fatalf("bob's workouts: got %v, %v", workouts, err)
+	}
+}
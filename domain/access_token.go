@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// AccessTokenPrefix starts every personal access token, so they can be told apart from session tokens.
+const AccessTokenPrefix = "gla_"
+
+// TokenScope limits what an access token can do.
+type TokenScope string
+
+const (
+	// ScopeRead only allows reading, GET requests.
+	ScopeRead TokenScope = "read"
+	// ScopeWrite allows everything a session can do with the data of the user.
+	ScopeWrite TokenScope = "write"
+)
+
+// AccessToken is a personal access token of a user, for clients that cannot use the session
+// cookies like scripts. Only the hash of the token is stored, the token itself is shown once.
+type AccessToken struct {
+	ID         int
+	UserID     int
+	Name       string
+	Scope      TokenScope
+	TokenHash  string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+// NewAccessToken validates the name, scope and optional expiry of a new access token.
+func NewAccessToken(name string, scope TokenScope, expiresAt *time.Time, now time.Time) (AccessToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return AccessToken{}, invalid("name", "token name is required")
+	}
+	if len(name) > 100 {
+		return AccessToken{}, invalid("name", "token name cannot be longer than 100 characters")
+	}
+	if scope != ScopeRead && scope != ScopeWrite {
+		return AccessToken{}, invalid("scope", `token scope must be "read" or "write"`)
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return AccessToken{}, invalid("expires_at", "token expiry must be in the future")
+	}
+	return AccessToken{Name: name, Scope: scope, ExpiresAt: expiresAt}, nil
+}
+
+// IsExpired reports whether the token can no longer be used at the given time.
+func (t AccessToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// CanWrite reports whether the token can change data and not only read it.
+func (t AccessToken) CanWrite() bool {
+	return t.Scope == ScopeWrite
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// DataExportStatus tells whether the archive of a data export was built.
+type DataExportStatus string
+
+const (
+	DataExportPending  DataExportStatus = "pending"
+	DataExportBuilding DataExportStatus = "building"
+	DataExportReady    DataExportStatus = "ready"
+	DataExportFailed   DataExportStatus = "failed"
+)
+
+// DataExportTTL is how long the archive of a data export can be downloaded after it was built.
+const DataExportTTL = 24 * time.Hour
+
+// DataExport is a copy of everything gymlog holds about a user, built in the background as a
+// ZIP archive that the user downloads until ExpiresAt.
+type DataExport struct {
+	ID          int
+	UserID      int
+	Status      DataExportStatus
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	// ExpiresAt is nil while the export is pending or building, afterwards the archive is deleted at ExpiresAt.
+	ExpiresAt *time.Time
+}
+
+// IsExpired reports whether the archive of the export can no longer be downloaded.
+func (e DataExport) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// InProgress reports whether the archive of the export is still to be built.
+func (e DataExport) InProgress() bool {
+	return e.Status == DataExportPending || e.Status == DataExportBuilding
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "fmt"
+
+// ValidationError is an invalid value of an entity, Field is the name of the value in the API,
+// like "username" or "movement_pattern".
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(field, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func invalidf(field, format string, args ...any) error {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
@@ -1,8 +1,243 @@
 package domain
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
 // exercise defines a single exercise, for example push up.
+// Target is its main muscle and is always part of PrimaryMuscles.
+// Slug is the stable identifier of catalog exercises and is empty for exercises created by a user,
+// whose OwnerID is set instead.
 type Exercise struct {
-	ID     int
-	Name   string
-	Target string
+	ID               int
+	Slug             string
+	Name             string
+	Target           string
+	Equipment        string
+	Mechanics        string
+	MovementPattern  string
+	Unilateral       bool
+	PrimaryMuscles   []string
+	SecondaryMuscles []string
+	Instructions     string
+	OwnerID          *int
+}
+
+// Muscles are the muscle groups exercises can work.
+var Muscles = []string{
+	"abductors", "abs", "adductors", "biceps", "calves", "cardiovascular system", "delts", "forearms", "glutes",
+	"hamstrings", "lats", "levator scapulae", "pectorals", "quads", "serratus anterior", "spine", "traps", "triceps", "upper back",
+}
+
+// Equipments are the kinds of equipment an exercise can need.
+var Equipments = []string{
+	"barbell", "ez bar", "trap bar", "dumbbell", "kettlebell", "cable", "machine", "smith machine", "band",
+	"medicine ball", "stability ball", "foam roller", "rope", "plate", "suspension", "bodyweight",
+}
+
+// Mechanics tell whether an exercise moves several joints (compound) or a single one (isolation).
+var Mechanics = []string{"compound", "isolation"}
+
+// MovementPatterns group exercises by the movement they train.
+var MovementPatterns = []string{
+	"squat", "hinge", "lunge", "horizontal_push", "vertical_push", "horizontal_pull", "vertical_pull", "carry", "core",
+}
+
+// CreateExercise normalizes and validates an exercise. Equipment, mechanics and movement pattern are optional.
+func CreateExercise(exercise Exercise) (Exercise, error) {
+	exercise.Name = strings.TrimSpace(exercise.Name)
+	exercise.Target = normalizeTerm(exercise.Target)
+	exercise.Equipment = normalizeTerm(exercise.Equipment)
+	exercise.Mechanics = normalizeTerm(exercise.Mechanics)
+	exercise.MovementPattern = normalizeTerm(exercise.MovementPattern)
+	exercise.Instructions = strings.TrimSpace(exercise.Instructions)
+
+	if exercise.Name == "" {
+		return Exercise{}, invalid("name", "name is required")
+	}
+	if exercise.Target == "" {
+		return Exercise{}, invalid("target", "target is required")
+	}
+	if err := checkTerm("target", "target", exercise.Target, Muscles); err != nil {
+		return Exercise{}, err
+	}
+	if err := checkTerm("equipment", "equipment", exercise.Equipment, Equipments); err != nil {
+		return Exercise{}, err
+	}
+	if err := checkTerm("mechanics", "mechanics", exercise.Mechanics, Mechanics); err != nil {
+		return Exercise{}, err
+	}
+	if err := checkTerm("movement_pattern", "movement pattern", exercise.MovementPattern, MovementPatterns); err != nil {
+		return Exercise{}, err
+	}
+
+	// The target always comes first and a muscle is either primary or secondary, never both.
+	primary := []string{exercise.Target}
+	seen := map[string]bool{exercise.Target: true}
+	for _, muscle := range exercise.PrimaryMuscles {
+		muscle = normalizeTerm(muscle)
+		if err := checkTerm("primary_muscles", "muscle", muscle, Muscles); err != nil {
+			return Exercise{}, err
+		}
+		if !seen[muscle] {
+			primary = append(primary, muscle)
+			seen[muscle] = true
+		}
+	}
+	secondary := []string{}
+	for _, muscle := range exercise.SecondaryMuscles {
+		muscle = normalizeTerm(muscle)
+		if err := checkTerm("secondary_muscles", "muscle", muscle, Muscles); err != nil {
+			return Exercise{}, err
+		}
+		if !seen[muscle] {
+			secondary = append(secondary, muscle)
+			seen[muscle] = true
+		}
+	}
+	exercise.PrimaryMuscles = primary
+	exercise.SecondaryMuscles = secondary
+	return exercise, nil
+}
+
+func normalizeTerm(term string) string {
+	return strings.ToLower(strings.TrimSpace(term))
+}
+
+// checkTerm makes sure a non empty value is one of the allowed ones.
+func checkTerm(field, term, value string, allowed []string) error {
+	if value == "" || slices.Contains(allowed, value) {
+		return nil
+	}
+	return invalidf(field, "unknown %s %q", term, value)
+}
+
+// ExerciseSort defines the order of the exercise catalog, a leading "-" means descending.
+type ExerciseSort string
+
+const (
+	SortByName       ExerciseSort = "name"
+	SortByNameDesc   ExerciseSort = "-name"
+	SortByTarget     ExerciseSort = "target"
+	SortByTargetDesc ExerciseSort = "-target"
+	SortByID         ExerciseSort = "id"
+	SortByIDDesc     ExerciseSort = "-id"
+)
+
+const (
+	DefaultExerciseLimit = 50
+	MaxExerciseLimit     = 200
+)
+
+// ExerciseFilter narrows the exercise catalog, empty fields match every exercise.
+// Muscle matches both primary and secondary muscles and Search matches the name.
+type ExerciseFilter struct {
+	Target          string
+	Muscle          string
+	Equipment       string
+	Mechanics       string
+	MovementPattern string
+	Unilateral      *bool
+	Search          string
+}
+
+// ExerciseQuery defines how to filter, search, sort and paginate the exercise catalog.
+// A zero Limit returns every matching exercise, NewExerciseQuery never leaves it at zero.
+type ExerciseQuery struct {
+	ExerciseFilter
+	Sort  ExerciseSort
+	After *ExerciseCursor
+	Limit int
+}
+
+// ExercisePage is a page of exercises, NextCursor is empty on the last page.
+type ExercisePage struct {
+	Exercises  []Exercise
+	NextCursor string
+}
+
+// ExerciseCursor points right after the last exercise of a page for a given sort.
+type ExerciseCursor struct {
+	Sort   ExerciseSort `json:"s"`
+	Name   string       `json:"n,omitempty"`
+	Target string       `json:"t,omitempty"`
+	ID     int          `json:"i"`
+}
+
+func NewExerciseQuery(filter ExerciseFilter, sort, cursor string, limit int) (ExerciseQuery, error) {
+	filter.Target = normalizeTerm(filter.Target)
+	filter.Muscle = normalizeTerm(filter.Muscle)
+	filter.Equipment = normalizeTerm(filter.Equipment)
+	filter.Mechanics = normalizeTerm(filter.Mechanics)
+	filter.MovementPattern = normalizeTerm(filter.MovementPattern)
+	filter.Search = strings.TrimSpace(filter.Search)
+
+	query := ExerciseQuery{
+		ExerciseFilter: filter,
+		Sort:           ExerciseSort(sort),
+		Limit:          limit,
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultExerciseLimit
+	}
+	if query.Sort == "" {
+		query.Sort = SortByName
+	}
+	switch query.Sort {
+	case SortByName, SortByNameDesc, SortByTarget, SortByTargetDesc, SortByID, SortByIDDesc:
+	default:
+		return ExerciseQuery{}, invalidf("sort", "unknown sort %q", sort)
+	}
+	if limit < 0 || limit > MaxExerciseLimit {
+		return ExerciseQuery{}, invalidf("limit", "limit cannot be negative or greater than %d", MaxExerciseLimit)
+	}
+	if cursor != "" {
+		after, err := ParseExerciseCursor(cursor)
+		if err != nil {
+			return ExerciseQuery{}, err
+		}
+		if after.Sort != query.Sort {
+			return ExerciseQuery{}, invalid("cursor", "cursor was created for a different sort")
+		}
+		query.After = &after
+	}
+	return query, nil
+}
+
+// Descending reports whether the query sorts in descending order.
+func (q ExerciseQuery) Descending() bool {
+	return strings.HasPrefix(string(q.Sort), "-")
+}
+
+// CursorAfter returns the cursor that continues the query right after the given exercise.
+func (q ExerciseQuery) CursorAfter(exercise Exercise) ExerciseCursor {
+	cursor := ExerciseCursor{Sort: q.Sort, ID: exercise.ID}
+	switch q.Sort {
+	case SortByName, SortByNameDesc:
+		cursor.Name = exercise.Name
+	case SortByTarget, SortByTargetDesc:
+		cursor.Name = exercise.Name
+		cursor.Target = exercise.Target
+	}
+	return cursor
+}
+
+func (c ExerciseCursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func ParseExerciseCursor(cursor string) (ExerciseCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ExerciseCursor{}, invalid("cursor", "invalid cursor")
+	}
+	var c ExerciseCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return ExerciseCursor{}, invalid("cursor", "invalid cursor")
+	}
+	return c, nil
 }
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// LoginOutcome is how a login attempt ended.
+type LoginOutcome string
+
+const (
+	LoginSucceeded LoginOutcome = "succeeded"
+	// LoginFailed is a login with an unknown username or a wrong password.
+	LoginFailed LoginOutcome = "failed"
+	// LoginLocked is a login rejected without checking the password, during a lockout.
+	LoginLocked LoginOutcome = "locked"
+)
+
+// LoginAttempt is the audit record of a login with a password.
+type LoginAttempt struct {
+	ID        int
+	Username  string
+	IP        string
+	Outcome   LoginOutcome
+	CreatedAt time.Time
+}
+
+// LockoutPolicy slows down password guessing: after FreeAttempts failed logins, every new
+// failure doubles the wait before the next attempt, from BaseDelay up to MaxDelay. Failures
+// older than Window are forgotten.
+type LockoutPolicy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Window       time.Duration
+}
+
+var (
+	// DefaultAccountLockout protects each account, a successful login starts over.
+	DefaultAccountLockout = LockoutPolicy{FreeAttempts: 5, BaseDelay: 30 * time.Second, MaxDelay: time.Hour, Window: 24 * time.Hour}
+	// DefaultIPLockout protects against one client guessing the passwords of many accounts.
+	DefaultIPLockout = LockoutPolicy{FreeAttempts: 20, BaseDelay: 30 * time.Second, MaxDelay: time.Hour, Window: time.Hour}
+)
+
+// LockedUntil returns when the next login can be tried after the failures, the last of them
+// at lastFailure. It is the zero time when there is no wait.
+func (p LockoutPolicy) LockedUntil(failures int, lastFailure time.Time) time.Time {
+	if failures < p.FreeAttempts {
+		return time.Time{}
+	}
+	delay := p.BaseDelay
+	for range failures - p.FreeAttempts {
+		if delay >= p.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	return lastFailure.Add(min(delay, p.MaxDelay))
+}
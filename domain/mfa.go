@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// TOTP is the authenticator app enrolled by a user for two-factor authentication. It only
+// protects the logins once ConfirmedAt is set, after the user typed a first code.
+type TOTP struct {
+	UserID      int
+	Secret      string
+	CreatedAt   time.Time
+	ConfirmedAt *time.Time
+	// LastStep is the TOTP step of the last code used, older codes are rejected so a code seen
+	// by someone else cannot be used again.
+	LastStep int64
+}
+
+// Enabled reports whether logins need a code from the authenticator app.
+func (t TOTP) Enabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// RecoveryCodeCount is how many single use recovery codes are given when enabling 2FA, to log
+// in without the authenticator app.
+const RecoveryCodeCount = 10
+
+const (
+	// MFAChallengeTTL is how long a user has to type the second factor after the password.
+	MFAChallengeTTL = 5 * time.Minute
+	// MFAChallengeAttempts is how many wrong codes a challenge accepts before the user has to
+	// type the password again.
+	MFAChallengeAttempts = 5
+)
+
+// MFAChallenge is a login that passed the password and waits for the second factor. Only the
+// hash of its token is stored.
+type MFAChallenge struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Attempts  int
+}
+
+// IsUsable reports whether the challenge can still complete a login at the given time.
+func (c MFAChallenge) IsUsable(now time.Time) bool {
+	return c.Attempts < MFAChallengeAttempts && now.Before(c.ExpiresAt)
+}
@@ -1,10 +1,10 @@
 package domain
 
-import "errors"
-
 // routine defines a list of exercises that compose a workout, for example push day.
+// UserID is the owner, the only one who can change it even when it is shared with other users.
 type Routine struct {
 	ID          int
+	UserID      int
 	Name        string
 	Description string
 	Exercises   []ExerciseDetail
@@ -34,10 +34,10 @@ func NewExerciseDetail(id int, sets, reps int) ExerciseDetail {
 
 func CreateRoutine(name, description string, exercises []ExerciseDetail) (Routine, error) {
 	if name == "" {
-		return Routine{}, errors.New("name is required")
+		return Routine{}, invalid("name", "name is required")
 	}
 	if len(exercises) == 0 {
-		return Routine{}, errors.New("at least one exercise is required")
+		return Routine{}, invalid("exercises", "at least one exercise is required")
 	}
 	return Routine{
 		Name:        name,
@@ -45,3 +45,58 @@ func CreateRoutine(name, description string, exercises []ExerciseDetail) (Routin
 		Exercises:   exercises,
 	}, nil
 }
+
+// RoutinePatch defines a partial update of a routine, nil fields are left untouched.
+// Order reorders the current exercises by their position, starting at 0, and must contain each
+// position exactly once, so a routine can list the same exercise more than once.
+type RoutinePatch struct {
+	Name        *string
+	Description *string
+	Exercises   *[]ExerciseDetail
+	Order       []int
+}
+
+// Apply returns a copy of the routine with the patch applied, validated as a new routine.
+func (r Routine) Apply(patch RoutinePatch) (Routine, error) {
+	name, description, exercises := r.Name, r.Description, r.Exercises
+	if patch.Name != nil {
+		name = *patch.Name
+	}
+	if patch.Description != nil {
+		description = *patch.Description
+	}
+	if patch.Exercises != nil {
+		exercises = *patch.Exercises
+	}
+	if patch.Order != nil {
+		reordered, err := reorderExercises(exercises, patch.Order)
+		if err != nil {
+			return Routine{}, err
+		}
+		exercises = reordered
+	}
+
+	patched, err := CreateRoutine(name, description, exercises)
+	if err != nil {
+		return Routine{}, err
+	}
+	patched.ID = r.ID
+	patched.UserID = r.UserID
+	return patched, nil
+}
+
+func reorderExercises(exercises []ExerciseDetail, order []int) ([]ExerciseDetail, error) {
+	if len(order) != len(exercises) {
+		return nil, invalid("order", "order must list every exercise of the routine")
+	}
+	reordered := make([]ExerciseDetail, 0, len(order))
+	seen := make([]bool, len(exercises))
+	for _, position := range order {
+		if position < 0 || position >= len(exercises) || seen[position] {
+			return nil, invalidf("order", "position %d is not in the routine or is repeated", position)
+		}
+		reordered = append(reordered, exercises[position])
+		seen[position] = true
+	}
+	return reordered, nil
+}
@@ -1,15 +1,184 @@
 package domain
 
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
 // user defines a user of the application.
 type User struct {
 	ID           int
 	Username     string
 	Email        string
 	PasswordHash string
+	// EmailVerifiedAt is nil until the user confirms they own Email.
+	EmailVerifiedAt *time.Time
+	// DeletionScheduledAt is when the account and all its data will be deleted, nil unless the
+	// user asked to delete it.
+	DeletionScheduledAt *time.Time
+}
+
+// EmailVerified reports whether the user confirmed their email.
+func (u User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// DeletionPending reports whether the user asked to delete their account and can still cancel it.
+func (u User) DeletionPending() bool {
+	return u.DeletionScheduledAt != nil
 }
 
+// AccountDeletionGracePeriod is how long an account is kept after the user asks to delete it,
+// they can cancel the deletion until then.
+const AccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+var ErrInvalidEmail = invalid("email", "email is not a valid address")
+
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 32
+	MinPasswordLength = 8
+	// MaxPasswordLength is the most bcrypt reads, it would silently ignore the rest.
+	MaxPasswordLength = 72
+)
+
+var validUsername = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// CreateUser validates the username and email of a new user. Usernames and emails are unique
+// ignoring case, which the storage enforces. The password is checked with ValidatePassword
+// before hashing it.
+func CreateUser(user User) (User, error) {
+	if len(user.Username) < MinUsernameLength || len(user.Username) > MaxUsernameLength {
+		return User{}, invalidf("username", "username must have between %d and %d characters", MinUsernameLength, MaxUsernameLength)
+	}
+	if !validUsername.MatchString(user.Username) {
+		return User{}, invalid("username", "username can only have letters, digits, '_', '.' and '-', and must start with a letter or digit")
+	}
+	if err := ValidateEmail(user.Email); err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+// ValidatePassword checks the password policy.
+func ValidatePassword(password string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return invalidf("password", "password must have at least %d characters", MinPasswordLength)
+	}
+	if len(password) > MaxPasswordLength {
+		return invalidf("password", "password cannot be longer than %d bytes", MaxPasswordLength)
+	}
+	if strings.TrimSpace(password) == "" {
+		return invalid("password", "password cannot be only spaces")
+	}
+	return nil
+}
+
+// NormalizeUsername returns the form of a username used to compare it, usernames are unique
+// ignoring case.
+func NormalizeUsername(username string) string {
+	return strings.ToLower(username)
+}
+
+// ValidateEmail checks that the email is a plain address like "name@example.com", without a
+// display name or angle brackets.
+func ValidateEmail(email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Name != "" || address.Address != email {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+// UserSession is a logged in device of a user, a user can have many at the same time.
 type UserSession struct {
+	ID           int
 	UserID       int
 	SessionToken string
 	CSRFToken    string
+	DeviceName   string
+	UserAgent    string
+	IP           string
+	CreatedAt    time.Time
+	LastSeenAt   time.Time
+	ExpiresAt    time.Time
+}
+
+// SessionPolicy defines how long sessions last: every use renews a session for IdleTimeout
+// (sliding window), but a session never outlives MaxLifetime since the login.
+type SessionPolicy struct {
+	IdleTimeout time.Duration
+	MaxLifetime time.Duration
+}
+
+var DefaultSessionPolicy = SessionPolicy{
+	IdleTimeout: 24 * time.Hour,
+	MaxLifetime: 30 * 24 * time.Hour,
+}
+
+func (p SessionPolicy) Validate() error {
+	if p.IdleTimeout <= 0 || p.MaxLifetime <= 0 {
+		return errors.New("session idle timeout and max lifetime must be positive")
+	}
+	if p.IdleTimeout > p.MaxLifetime {
+		return errors.New("session idle timeout cannot be longer than the max lifetime")
+	}
+	return nil
+}
+
+// ExpiresAt returns when a session created and last used at the given times expires.
+func (p SessionPolicy) ExpiresAt(createdAt, lastSeenAt time.Time) time.Time {
+	idle := lastSeenAt.Add(p.IdleTimeout)
+	if deadline := createdAt.Add(p.MaxLifetime); deadline.Before(idle) {
+		return deadline
+	}
+	return idle
+}
+
+// IsExpired reports whether the session can no longer be used at the given time.
+func (s UserSession) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// PasswordResetTTL is how long a password reset token can be used after it was requested.
+const PasswordResetTTL = time.Hour
+
+// PasswordReset is a request to reset the password of a user. Only the hash of its token is
+// stored and the token can only be used once, before ExpiresAt.
+type PasswordReset struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// IsUsable reports whether the reset can still change the password at the given time.
+func (p PasswordReset) IsUsable(now time.Time) bool {
+	return p.UsedAt == nil && now.Before(p.ExpiresAt)
+}
+
+// EmailVerificationTTL is how long an email verification token can be used after it was sent.
+const EmailVerificationTTL = 24 * time.Hour
+
+// EmailVerification is a token sent to an email to confirm that the user owns it. Only the hash
+// of the token is stored, and it only verifies Email, not an email the user changed to later.
+type EmailVerification struct {
+	ID        int
+	UserID    int
+	Email     string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// IsUsable reports whether the token can still verify the email at the given time.
+func (v EmailVerification) IsUsable(now time.Time) bool {
+	return v.UsedAt == nil && now.Before(v.ExpiresAt)
 }
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// workout defines a training session that was actually performed, optionally following a routine.
+type Workout struct {
+	ID         int
+	RoutineID  *int
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Sets       []WorkoutSet
+}
+
+// WorkoutSet defines a single set performed during a workout, for example 6 reps at 100 kg.
+type WorkoutSet struct {
+	ExerciseID int
+	SetIndex   int
+	Reps       int
+	Weight     float64
+	Completed  bool
+}
+
+func StartWorkout(routineID *int, startedAt time.Time) Workout {
+	return Workout{
+		RoutineID: routineID,
+		StartedAt: startedAt.UTC(),
+		Sets:      []WorkoutSet{},
+	}
+}
+
+func NewWorkoutSet(exerciseID, setIndex, reps int, weight float64, completed bool) (WorkoutSet, error) {
+	if exerciseID <= 0 {
+		return WorkoutSet{}, invalid("exercise_id", "exercise id is required")
+	}
+	if setIndex < 0 {
+		return WorkoutSet{}, invalid("set_index", "set index cannot be negative")
+	}
+	if reps < 0 {
+		return WorkoutSet{}, invalid("reps", "reps cannot be negative")
+	}
+	if weight < 0 {
+		return WorkoutSet{}, invalid("weight", "weight cannot be negative")
+	}
+	return WorkoutSet{
+		ExerciseID: exerciseID,
+		SetIndex:   setIndex,
+		Reps:       reps,
+		Weight:     weight,
+		Completed:  completed,
+	}, nil
+}
+
+// IsFinished reports whether the workout has already been closed.
+func (w Workout) IsFinished() bool {
+	return w.FinishedAt != nil
+}
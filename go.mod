@@ -3,6 +3,7 @@ module gymlog
 go 1.24.4
 
 require (
-	github.com/mattn/go-sqlite3 v1.14.33 // indirect
+	github.com/lib/pq v1.9.0
+	github.com/mattn/go-sqlite3 v1.14.33
 	golang.org/x/crypto v0.47.0
 )
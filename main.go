@@ -1,19 +1,157 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"gymlog/adapters/application"
+	"gymlog/adapters/mailer"
 	"gymlog/adapters/server"
 	"gymlog/adapters/storage"
+	"gymlog/domain"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// defaultDatabaseURL is used when GYMLOG_DATABASE_URL is not set.
+const defaultDatabaseURL = "gymlog.db"
+
+const (
+	// maintenanceInterval is how often expired data is cleaned up and scheduled account deletions run.
+	maintenanceInterval = 10 * time.Minute
+	// dataExportInterval is how often the pending data exports are built.
+	dataExportInterval = 10 * time.Second
+	// shutdownTimeout is how long the running requests have to finish on shutdown.
+	shutdownTimeout = 10 * time.Second
 )
 
 func main() {
-	storage, err := storage.NewSqliteStorage("gymlog.db")
+	databaseURL := os.Getenv("GYMLOG_DATABASE_URL")
+	if databaseURL == "" {
+		databaseURL = defaultDatabaseURL
+	}
+
+	// "gymlog migrate" only applies the pending schema migrations, the server also applies them on startup.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		applied, err := storage.Migrate(databaseURL)
+		for _, name := range applied {
+			log.Println("Applied migration", name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Database is up to date (%d migrations applied)", len(applied))
+		return
+	}
+
+	sessionPolicy, err := loadSessionPolicy()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mailer, err := loadMailer()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serverConfig, err := loadServerConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	storage, err := storage.Open(databaseURL)
 	if err != nil {
 		log.Fatal(err)
 	}
 	routineRepository := application.NewGymRepository(storage)
-	userRepository := application.NewUserRepo(storage)
-	gymlogServer := server.NewServer(routineRepository, userRepository)
-	log.Fatal(gymlogServer.Start())
+	userRepository := application.NewUserRepo(storage, sessionPolicy, mailer)
+	gymlogServer := server.NewServer(routineRepository, userRepository, serverConfig)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		application.RunMaintenance(ctx, userRepository, maintenanceInterval)
+	}()
+	go func() {
+		defer wg.Done()
+		application.RunDataExporter(ctx, userRepository, dataExportInterval)
+	}()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- gymlogServer.Start()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := gymlogServer.Shutdown(shutdownCtx); err != nil {
+			log.Println("Error shutting down server:", err)
+		}
+	}
+	stop()
+	wg.Wait()
+	if err := storage.Close(); err != nil {
+		log.Println("Error closing storage:", err)
+	}
+}
+
+// loadSessionPolicy reads the session lifetimes from GYMLOG_SESSION_IDLE_TIMEOUT and
+// GYMLOG_SESSION_MAX_LIFETIME (like "30m" or "720h"), using the defaults when they are not set.
+func loadSessionPolicy() (domain.SessionPolicy, error) {
+	policy := domain.DefaultSessionPolicy
+	for name, duration := range map[string]*time.Duration{
+		"GYMLOG_SESSION_IDLE_TIMEOUT": &policy.IdleTimeout,
+		"GYMLOG_SESSION_MAX_LIFETIME": &policy.MaxLifetime,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return domain.SessionPolicy{}, fmt.Errorf("%s: %w", name, err)
+		}
+		*duration = parsed
+	}
+	return policy, policy.Validate()
+}
+
+// loadMailer returns a mailer for the SMTP server in GYMLOG_SMTP_ADDR ("host:port"), authenticated
+// with GYMLOG_SMTP_USERNAME and GYMLOG_SMTP_PASSWORD and sending from GYMLOG_MAIL_FROM. Without
+// GYMLOG_SMTP_ADDR the emails are written to the log instead.
+func loadMailer() (mailer.Mailer, error) {
+	addr := os.Getenv("GYMLOG_SMTP_ADDR")
+	if addr == "" {
+		log.Println("GYMLOG_SMTP_ADDR is not set, emails are written to the log")
+		return mailer.NewLogMailer(log.Writer()), nil
+	}
+	return mailer.NewSMTPMailer(addr, os.Getenv("GYMLOG_SMTP_USERNAME"), os.Getenv("GYMLOG_SMTP_PASSWORD"), os.Getenv("GYMLOG_MAIL_FROM"))
+}
+
+// loadServerConfig reads the options of the server. GYMLOG_REQUIRE_VERIFIED_EMAIL ("true" by
+// default) blocks sharing routines until the user verifies their email.
+func loadServerConfig() (server.Config, error) {
+	config := server.Config{RequireVerifiedEmail: true}
+	if value := os.Getenv("GYMLOG_REQUIRE_VERIFIED_EMAIL"); value != "" {
+		required, err := strconv.ParseBool(value)
+		if err != nil {
+			return server.Config{}, fmt.Errorf("GYMLOG_REQUIRE_VERIFIED_EMAIL: %w", err)
+		}
+		config.RequireVerifiedEmail = required
+	}
+	return config, nil
 }